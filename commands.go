@@ -0,0 +1,865 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/m3o/m3o-client-gen/gen"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// cacheFile is the file in the root folder remembering the inputs of the
+// services generated, see gen.Cache
+const cacheFile = ".m3o-gen-cache.json"
+
+// manifestFile is the file in the root folder listing the files generated,
+// see gen.Manifest
+const manifestFile = ".m3o-gen-manifest.json"
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
+
+var commands = []command{
+	{name: "generate", description: "generate clients and examples for the given targets", run: generateCmd},
+	{name: "check", description: "fail if the generated files on disk are out of date for the given targets", run: checkCmd},
+	{name: "validate", description: "check the specs of every service can be loaded and its examples match them", run: validateCmd},
+	{name: "lint", description: "check the specs and protos follow the conventions the generators rely on", run: lintCmd},
+	{name: "coverage", description: "report which endpoints of every service have examples", run: coverageCmd},
+	{name: "diff", description: "show what generating the given targets would change on disk", run: diffCmd},
+	{name: "watch", description: "regenerate the services of the given targets whenever their specs, protos or examples change", run: watchCmd},
+	{name: "list", description: "list the supported targets or the services found in the root folder", run: listCmd},
+	{name: "version", description: "print the version of the generator", run: versionCmd},
+}
+
+// options are the flags shared by the commands working on a services tree
+type options struct {
+	root      string
+	out       string
+	examples  string
+	lang      string
+	targets   []gen.Target
+	filter    serviceFilter
+	jobs      int
+	noMake    bool
+	strict    bool
+	specDir   string
+	fromProto bool
+	// folders the imports of the service protos are looked up in
+	protoPaths pathsFlag
+	config     *config
+	// set with -config, otherwise looked up in the root folder
+	configPath string
+	// nil unless generating to disk
+	cache    *gen.Cache
+	manifest *gen.Manifest
+	// shared by the targets of a run, nil for a cache per target
+	protos *gen.ProtoCache
+}
+
+// listFlag is a flag that can be repeated and takes comma separated values
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if _, err := filepath.Match(v, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", v, err)
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// pathsFlag is a flag that can be repeated and takes folders separated by
+// the path list separator of the os, like the --proto_path of protoc
+type pathsFlag []string
+
+func (p *pathsFlag) String() string {
+	return strings.Join(*p, string(filepath.ListSeparator))
+}
+
+func (p *pathsFlag) Set(value string) error {
+	for _, v := range filepath.SplitList(value) {
+		if v != "" {
+			*p = append(*p, v)
+		}
+	}
+	return nil
+}
+
+func (o *options) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&o.root, "root", ".", "folder containing the service folders and their specs")
+	fs.StringVar(&o.out, "out", "", "output folder for the clients, overrides the folders set in the config file (default <root>/clients)")
+	fs.StringVar(&o.examples, "examples", "", "output folder for the examples (default <root>/examples)")
+	fs.StringVar(&o.configPath, "config", "", "config file (default <root>/m3o-gen.yaml, m3o-gen.yml or m3o-gen.json if it exists)")
+	fs.StringVar(&o.lang, "lang", "", "comma separated list of targets e.g. go,ts, targets can also be passed as arguments")
+	fs.Var(&o.filter.include, "service", "only generate the services matching these names or glob patterns, can be repeated")
+	fs.Var(&o.filter.exclude, "exclude", "skip the services matching these names or glob patterns, can be repeated")
+	fs.IntVar(&o.jobs, "jobs", runtime.NumCPU(), "number of services processed concurrently")
+	fs.BoolVar(&o.noMake, "no-make", false, "use the api json specs on disk instead of building them with 'make api'")
+	fs.BoolVar(&o.strict, "strict", false, "fail a service when 'make api' fails instead of using the spec already on disk")
+	fs.StringVar(&o.specDir, "spec-dir", "", "folder of <service>.json and <service>.proto files to use instead of the specs in the service folders, implies -no-make")
+	fs.BoolVar(&o.fromProto, "proto", false, "derive the specs from the service protos instead of the api json files, implies -no-make")
+	fs.Var(&o.protoPaths, "proto-path", "folder the imports of the service protos are looked up in after the folder of each proto, can be repeated")
+	return fs
+}
+
+// parse parses the command line, resolves the folders to absolute paths and
+// looks up the requested targets. On failure it returns the exit code the
+// command should return.
+func (o *options) parse(fs *flag.FlagSet, args []string, needTargets bool) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitBadInput, false
+	}
+	return o.resolve(fs.Name(), fs.Args(), needTargets)
+}
+
+// resolve validates the parsed flags and looks up the given targets
+func (o *options) resolve(command string, names []string, needTargets bool) (int, bool) {
+	if o.jobs < 1 {
+		fmt.Fprintf(os.Stderr, "invalid -jobs %v, at least 1 job is needed\n", o.jobs)
+		return exitBadInput, false
+	}
+
+	root, err := filepath.Abs(o.root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitBadInput, false
+	}
+	if fi, err := os.Stat(root); err != nil || !fi.IsDir() {
+		fmt.Fprintf(os.Stderr, "root %v is not a folder\n", o.root)
+		return exitBadInput, false
+	}
+	o.root = root
+	if o.examples == "" {
+		o.examples = filepath.Join(root, "examples")
+	}
+	if o.out != "" {
+		if o.out, err = filepath.Abs(o.out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitBadInput, false
+		}
+	}
+	if o.examples, err = filepath.Abs(o.examples); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitBadInput, false
+	}
+	if o.specDir != "" {
+		if o.specDir, err = filepath.Abs(o.specDir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitBadInput, false
+		}
+		if fi, err := os.Stat(o.specDir); err != nil || !fi.IsDir() {
+			fmt.Fprintf(os.Stderr, "spec folder %v is not a folder\n", o.specDir)
+			return exitBadInput, false
+		}
+		o.noMake = true
+	}
+
+	if o.config, err = loadConfig(root, o.configPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitBadInput, false
+	}
+	// the proto paths of the config file are relative to the root folder
+	for _, path := range o.config.ProtoPaths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		o.protoPaths = append(o.protoPaths, path)
+	}
+	for i, path := range o.protoPaths {
+		if o.protoPaths[i], err = filepath.Abs(path); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitBadInput, false
+		}
+	}
+
+	if o.lang != "" {
+		names = append(strings.Split(o.lang, ","), names...)
+	}
+	for _, name := range names {
+		t, ok := gen.FindTarget(strings.TrimSpace(name))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown target %q, run 'm3o-client-gen list' to see the supported targets\n", name)
+			return exitBadInput, false
+		}
+		o.targets = append(o.targets, t)
+	}
+	if needTargets && len(o.targets) == 0 {
+		fmt.Fprintf(os.Stderr, "no target given, usage: m3o-client-gen %v [flags] <target>...\n", command)
+		return exitBadInput, false
+	}
+
+	// catch typos, a pattern matching nothing would silently generate nothing
+	if len(o.filter.include) > 0 {
+		names, err := o.serviceDirs()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure, false
+		}
+		for _, pattern := range o.filter.include {
+			if !(serviceFilter{include: []string{pattern}}).match(names...) {
+				fmt.Fprintf(os.Stderr, "no service matches %q\n", pattern)
+				return exitBadInput, false
+			}
+		}
+	}
+	return exitOK, true
+}
+
+// clientPath returns the folder the clients of a target are written to, empty
+// for targets that only produce examples
+func (o *options) clientPath(t gen.Target) string {
+	switch {
+	case t.ClientDir == "":
+		return ""
+	case o.out != "":
+		return filepath.Join(o.out, t.ClientDir)
+	case filepath.IsAbs(o.config.target(t.Name).Output):
+		return o.config.target(t.Name).Output
+	case o.config.target(t.Name).Output != "":
+		return filepath.Join(o.root, o.config.target(t.Name).Output)
+	}
+	return filepath.Join(o.root, "clients", t.ClientDir)
+}
+
+// serviceDirs returns the service folders of the root folder, leaving out the
+// folders the clients and examples are written to
+func (o *options) serviceDirs() ([]string, error) {
+	exclude := []string{o.examples}
+	for _, t := range gen.Targets {
+		if path := o.clientPath(t); path != "" {
+			exclude = append(exclude, path)
+		}
+	}
+	return gen.ServiceDirs(o.root, exclude)
+}
+
+// genOptions returns the options of a generator run for the services of the
+// root folder, without a target
+func (o *options) genOptions() (gen.Options, error) {
+	names, err := o.serviceDirs()
+	if err != nil {
+		return gen.Options{}, err
+	}
+	return gen.Options{
+		Root:         o.root,
+		ExamplesPath: o.examples,
+		Services:     names,
+		Match: func(serviceName string) bool {
+			return o.filter.match(serviceName)
+		},
+		Jobs:       o.jobs,
+		NoMake:     o.noMake,
+		Strict:     o.strict,
+		SpecDir:    o.specDir,
+		FromProto:  o.fromProto,
+		ProtoPaths: o.protoPaths,
+	}, nil
+}
+
+// rel returns path relative to the root folder when possible
+func (o *options) rel(path string) string {
+	rel, err := filepath.Rel(o.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func generateCmd(args []string) int {
+	return runGenerate("generate", args, false)
+}
+
+func diffCmd(args []string) int {
+	return runGenerate("diff", args, true)
+}
+
+// runGenerate generates the requested targets, either to disk or, for dry
+// runs and diffs, into memory to compare against the files on disk
+func runGenerate(name string, args []string, diff bool) int {
+	opts := &options{}
+	fs := opts.flagSet(name)
+	dryRun := fs.Bool("dry-run", false, "render into memory and list the files that would be written")
+	if !diff {
+		fs.BoolVar(&diff, "diff", false, "render into memory and print a unified diff against the files on disk")
+	}
+	force := fs.Bool("force", false, "regenerate every service, even the ones unchanged since the last run")
+	prune := fs.Bool("prune", false, "delete the files generated by previous runs that this run didn't produce")
+	if code, ok := opts.parse(fs, args, true); !ok {
+		return code
+	}
+	opts.protos = gen.NewProtoCache()
+
+	var out gen.Output = gen.DiskOutput{}
+	mem := gen.NewMemOutput()
+	if *dryRun || diff {
+		out = mem
+	}
+
+	// dry runs and diffs need every file rendered to compare them
+	if out == gen.Output(gen.DiskOutput{}) {
+		v := generatorVersion()
+		cache, err := gen.LoadCache(filepath.Join(opts.root, cacheFile), v)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+		// a development build that can't be told apart from others could
+		// reuse the output of a generator with different code
+		if *force || v == "dev" {
+			cache.Services = map[string]string{}
+		}
+		opts.cache = cache
+
+		manifest, err := gen.LoadManifest(filepath.Join(opts.root, manifestFile))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+		opts.manifest = manifest
+	}
+	// files listed by the manifest before the run
+	previous := []string{}
+	if opts.manifest != nil {
+		previous = append(opts.manifest.Paths(), opts.manifest.Orphaned...)
+	}
+
+	// failing services don't stop the run, they're reported at the end
+	failures := []*gen.ServiceError{}
+	for _, t := range opts.targets {
+		if err := generateTarget(t, out, opts); err != nil {
+			failures = append(failures, serviceErrors(t.Name, err)...)
+		}
+	}
+	code := exitOK
+	if len(failures) > 0 {
+		code = exitFailure
+	}
+	defer printFailures(os.Stderr, failures)
+
+	if opts.cache != nil {
+		if err := opts.cache.Save(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+	}
+	if opts.manifest != nil {
+		if err := pruneFiles(opts, previous, *prune); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+	}
+
+	if out == gen.Output(gen.DiskOutput{}) {
+		return code
+	}
+	for _, path := range mem.Paths() {
+		rendered := mem.File(path)
+		status, current, err := compare(path, rendered)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+		rel := opts.rel(path)
+		if diff {
+			from := "a/" + rel
+			if status == fileNew {
+				from = "/dev/null"
+			}
+			unifiedDiff(os.Stdout, from, "b/"+rel, string(current), string(rendered))
+			continue
+		}
+		fmt.Printf("%-10v %v\n", status, rel)
+	}
+	return code
+}
+
+// pruneFiles finds the files listed in the manifest before the run that the
+// run didn't produce, deleting them along with the folders left empty when
+// prune is set, and saves the manifest
+func pruneFiles(opts *options, previous []string, prune bool) error {
+	produced := map[string]bool{}
+	for _, path := range opts.manifest.Paths() {
+		produced[path] = true
+	}
+	orphaned := []string{}
+	seen := map[string]bool{}
+	for _, path := range previous {
+		if !produced[path] && !seen[path] {
+			seen[path] = true
+			orphaned = append(orphaned, path)
+		}
+	}
+	sort.Strings(orphaned)
+
+	opts.manifest.Orphaned = nil
+	for _, path := range orphaned {
+		if !prune {
+			opts.manifest.Orphaned = append(opts.manifest.Orphaned, path)
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		fmt.Printf("%-10v %v\n", "pruned", opts.rel(path))
+		// os.Remove fails on folders that aren't empty, which stops the walk
+		for dir := filepath.Dir(path); strings.HasPrefix(dir, opts.root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	if len(opts.manifest.Orphaned) > 0 {
+		fmt.Printf("%v file(s) no longer generated, run with -prune to delete them:\n", len(opts.manifest.Orphaned))
+		for _, path := range opts.manifest.Orphaned {
+			fmt.Printf("  %v\n", opts.rel(path))
+		}
+	}
+	return opts.manifest.Save()
+}
+
+// generateTarget runs the generator of a target over the services of the
+// root folder
+func generateTarget(t gen.Target, out gen.Output, opts *options) error {
+	genOpts, err := opts.targetOptions(t, out)
+	if err != nil {
+		return err
+	}
+	return gen.Generate(context.Background(), genOpts)
+}
+
+// targetOptions returns the options of a generator run of a target
+func (o *options) targetOptions(t gen.Target, out gen.Output) (gen.Options, error) {
+	genOpts, err := o.genOptions()
+	if err != nil {
+		return gen.Options{}, err
+	}
+	genOpts.Target = t.Name
+	genOpts.Config = o.config.target(t.Name)
+	genOpts.Output = out
+	genOpts.ClientPath = o.clientPath(t)
+	genOpts.Cache = o.cache
+	genOpts.Manifest = o.manifest
+	genOpts.Protos = o.protos
+	return genOpts, nil
+}
+
+// checkCmd regenerates the targets in memory and compares the result with
+// the files on disk, reporting stale, missing and extra files
+func checkCmd(args []string) int {
+	opts := &options{}
+	fs := opts.flagSet("check")
+	if code, ok := opts.parse(fs, args, true); !ok {
+		return code
+	}
+	opts.protos = gen.NewProtoCache()
+
+	names, err := opts.serviceDirs()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	problems := 0
+	failures := []*gen.ServiceError{}
+	for _, t := range opts.targets {
+		mem := gen.NewMemOutput()
+		// the files of failed services aren't rendered, they'd show up as extra
+		failed := map[string]bool{}
+		if err := generateTarget(t, mem, opts); err != nil {
+			for _, serr := range serviceErrors(t.Name, err) {
+				failed[serr.Service] = true
+				failures = append(failures, serr)
+			}
+		}
+
+		for _, path := range mem.Paths() {
+			status, _, err := compare(path, mem.File(path))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return exitFailure
+			}
+			switch status {
+			case fileNew:
+				fmt.Printf("%-8v %v\n", "missing", opts.rel(path))
+				problems++
+			case fileChanged:
+				fmt.Printf("%-8v %v\n", "stale", opts.rel(path))
+				problems++
+			}
+		}
+
+		// files left over in the folders of the services, e.g. from a
+		// removed endpoint or example
+		for _, name := range names {
+			if !opts.filter.match(name) || gen.Skipped(filepath.Join(opts.root, name)) || failed[name] {
+				continue
+			}
+			for _, folder := range t.ServiceFolders(opts.clientPath(t), opts.examples, name) {
+				err := filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+					if os.IsNotExist(err) {
+						return nil
+					}
+					if err != nil || info.IsDir() || mem.Has(path) || t.Ignored(path) {
+						return err
+					}
+					fmt.Printf("%-8v %v\n", "extra", opts.rel(path))
+					problems++
+					return nil
+				})
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					return exitFailure
+				}
+			}
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("%v generated file(s) out of date, regenerate them with 'm3o-client-gen generate'\n", problems)
+	}
+	if len(failures) > 0 {
+		printFailures(os.Stderr, failures)
+		return exitFailure
+	}
+	if problems > 0 {
+		return exitStale
+	}
+	return exitOK
+}
+
+func validateCmd(args []string) int {
+	opts := &options{}
+	fs := opts.flagSet("validate")
+	if code, ok := opts.parse(fs, args, false); !ok {
+		return code
+	}
+
+	// validate the specs as they are on disk
+	opts.noMake = true
+	genOpts, err := opts.genOptions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	problems := 0
+	for _, name := range genOpts.Services {
+		if !opts.filter.match(name) {
+			continue
+		}
+		serviceDir := filepath.Join(opts.root, name)
+		spec, skip, err := gen.ServiceSpec(context.Background(), name, genOpts)
+		if skip {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("%v: %v\n", name, err)
+			problems++
+			continue
+		}
+		examples, err := gen.LoadExamples(serviceDir)
+		if err != nil {
+			fmt.Printf("%v: %v\n", name, err)
+			problems++
+			continue
+		}
+		path := opts.rel(gen.ExamplesFile(serviceDir))
+		for _, p := range gen.ValidateExamples(name, spec, path, examples) {
+			fmt.Println(p)
+			problems++
+		}
+	}
+
+	if problems > 0 {
+		fmt.Printf("found %v problem(s)\n", problems)
+		return exitBadInput
+	}
+	return exitOK
+}
+
+// lintCmd checks the spec and proto of every service, see gen.Lint. The
+// severity of the rules is set in the lint section of the config file.
+func lintCmd(args []string) int {
+	opts := &options{}
+	fs := opts.flagSet("lint")
+	rules := fs.Bool("rules", false, "list the rules and their severity")
+	if code, ok := opts.parse(fs, args, false); !ok {
+		return code
+	}
+	severities, err := gen.LintSeverities(opts.config.Lint)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitBadInput
+	}
+	if *rules {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "RULE\tSEVERITY\tDESCRIPTION")
+		for _, r := range gen.LintRules {
+			fmt.Fprintf(w, "%v\t%v\t%v\n", r.Name, severities[r.Name], r.Description)
+		}
+		w.Flush()
+		return exitOK
+	}
+
+	// lint the specs as they are on disk
+	opts.noMake = true
+	genOpts, err := opts.genOptions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	errs, warnings := 0, 0
+	for _, name := range genOpts.Services {
+		if !opts.filter.match(name) {
+			continue
+		}
+		spec, skip, err := gen.ServiceSpec(context.Background(), name, genOpts)
+		if skip {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("%-7v %v: %v\n", gen.SeverityError, name, err)
+			errs++
+			continue
+		}
+		for _, issue := range gen.Lint(name, spec, gen.ProtoFile(name, genOpts), genOpts.ProtoPaths, severities) {
+			fmt.Println(issue)
+			if issue.Severity == gen.SeverityError {
+				errs++
+			} else {
+				warnings++
+			}
+		}
+	}
+
+	if errs+warnings > 0 {
+		fmt.Printf("found %v error(s) and %v warning(s)\n", errs, warnings)
+	}
+	if errs > 0 {
+		return exitBadInput
+	}
+	return exitOK
+}
+
+// coverageCmd reports the endpoints with and without examples. With -gate
+// it fails when an endpoint that isn't in the baseline report has no
+// examples, so new endpoints can't be released undocumented. A missing
+// baseline is bad input unless -new-baseline says there's none yet.
+func coverageCmd(args []string) int {
+	opts := &options{}
+	fs := opts.flagSet("coverage")
+	format := fs.String("format", "markdown", "format of the report, markdown or json")
+	output := fs.String("o", "", "file to write the report to (default stdout)")
+	gate := fs.String("gate", "", "json report of the last release, fail if an endpoint added since has no examples")
+	newBaseline := fs.Bool("new-baseline", false, "start from an empty baseline if the -gate report doesn't exist")
+	if code, ok := opts.parse(fs, args, false); !ok {
+		return code
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "invalid -format %q, use markdown or json\n", *format)
+		return exitBadInput
+	}
+
+	// the baseline is read before the report is written as they may be the
+	// same file
+	var baseline *gen.Coverage
+	if *gate != "" {
+		var err error
+		baseline, err = gen.LoadCoverage(*gate)
+		if os.IsNotExist(err) && *newBaseline {
+			// without a baseline every endpoint is new
+			baseline = &gen.Coverage{}
+		} else if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "%v doesn't exist, use -new-baseline to start a new baseline\n", *gate)
+			return exitBadInput
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitBadInput
+		}
+	}
+
+	// report on the specs as they are on disk
+	opts.noMake = true
+	genOpts, err := opts.genOptions()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	coverage := &gen.Coverage{}
+	for _, name := range genOpts.Services {
+		if !opts.filter.match(name) {
+			continue
+		}
+		spec, skip, err := gen.ServiceSpec(context.Background(), name, genOpts)
+		if skip {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", name, err)
+			return exitFailure
+		}
+		examples, err := gen.LoadExamples(filepath.Join(opts.root, name))
+		if os.IsNotExist(err) {
+			examples = map[string][]gen.Example{}
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", name, err)
+			return exitFailure
+		}
+		coverage.Add(gen.ExampleCoverage(name, spec, examples))
+	}
+
+	report := coverage.Markdown()
+	if *format == "json" {
+		if report, err = coverage.JSON(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitFailure
+		}
+	}
+	if *output == "" {
+		os.Stdout.Write(report)
+	} else if err := ioutil.WriteFile(*output, report, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+
+	if baseline == nil {
+		return exitOK
+	}
+	uncovered := coverage.NewUncovered(baseline)
+	if len(uncovered) == 0 {
+		return exitOK
+	}
+	fmt.Fprintf(os.Stderr, "%v endpoint(s) added since %v have no examples:\n", len(uncovered), *gate)
+	for _, endpoint := range uncovered {
+		fmt.Fprintf(os.Stderr, "  %v\n", endpoint)
+	}
+	return exitFailure
+}
+
+func listCmd(args []string) int {
+	opts := &options{}
+	fs := opts.flagSet("list")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: m3o-client-gen list [flags] [targets|services]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitBadInput
+	}
+	if code, ok := opts.resolve("list", nil, false); !ok {
+		return code
+	}
+
+	switch fs.Arg(0) {
+	case "", "targets":
+		for _, t := range gen.Targets {
+			fmt.Printf("%-10v %v\n", t.Name, t.Description)
+		}
+	case "services":
+		names, err := opts.serviceDirs()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitBadInput
+		}
+		for _, name := range names {
+			if !opts.filter.match(name) {
+				continue
+			}
+			if gen.Skipped(filepath.Join(opts.root, name)) {
+				fmt.Println(name, "(skipped)")
+				continue
+			}
+			fmt.Println(name)
+		}
+	default:
+		fs.Usage()
+		return exitBadInput
+	}
+	return exitOK
+}
+
+func versionCmd(args []string) int {
+	fmt.Println("m3o-client-gen", generatorVersion())
+	return exitOK
+}
+
+// generatorVersion returns the version set at build time, else the module
+// version of the build. Development builds get the vcs revision they're
+// built from or, when the tree had changes or the revision isn't known, a
+// hash of the executable, so the cache tells builds of different code apart.
+// It's dev when none is available.
+func generatorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if version != "dev" || !ok {
+		return version
+	}
+	// the pseudo-versions of trees with changes don't tell the changes apart
+	if v := info.Main.Version; v != "" && v != "(devel)" && !strings.HasSuffix(v, "+dirty") {
+		return v
+	}
+	settings := map[string]string{}
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+	if rev := settings["vcs.revision"]; rev != "" && settings["vcs.modified"] != "true" {
+		return version + "-" + rev
+	}
+	if hash, err := executableHash(); err == nil {
+		return version + "-" + hash
+	}
+	return version
+}
+
+// executableHash returns a hash of the running executable
+func executableHash() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/m3o/m3o-client-gen/gen"
+)
+
+// config files looked up in the root folder when no -config is given
+var configFiles = []string{"m3o-gen.yaml", "m3o-gen.yml", "m3o-gen.json"}
+
+// config holds the project settings of an m3o-gen.yaml or m3o-gen.json file,
+// the settings of a language override the project wide ones. For example:
+//
+//	token_env: MY_API_TOKEN
+//	api_url: https://api.example.com
+//	languages:
+//	  go:
+//	    import_path: go.example.com
+//	    output: sdk/go
+//	    optional: pointer
+//	  ts:
+//	    int64: bigint
+//	lint:
+//	  field_description: error
+type config struct {
+	languageConfig
+	Languages map[string]languageConfig `json:"languages"`
+	// severity of the lint rules by name, see gen.LintRules
+	Lint map[string]string `json:"lint"`
+	// folders the imports of the service protos are looked up in, relative
+	// to the root folder
+	ProtoPaths []string `json:"proto_paths"`
+}
+
+type languageConfig struct {
+	// name of the env var the examples read the api token from
+	TokenEnv string `json:"token_env"`
+	// url of the api gateway called by the examples
+	APIURL string `json:"api_url"`
+	// import path or package of the clients, e.g. go.m3o.com
+	ImportPath string `json:"import_path"`
+	// folder the clients are written to, relative to the root folder unless absolute
+	Output string `json:"output"`
+	// names used to import services, e.g. when a service name is a keyword
+	ImportNames map[string]string `json:"import_names"`
+	// TypeScript type of the 64 bit integers, see gen.Int64Types
+	Int64 string `json:"int64"`
+	// Go type of the proto3 optional fields, see gen.OptionalTypes
+	Optional string `json:"optional"`
+}
+
+// loadConfig reads the config file at path, or the first config file found
+// in root when path is empty
+func loadConfig(root, path string) (*config, error) {
+	if path == "" {
+		for _, name := range configFiles {
+			if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+				path = filepath.Join(root, name)
+				break
+			}
+		}
+	}
+
+	if path == "" {
+		return &config{}, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// yaml is converted to json so both formats share the json tags
+	file := &config{}
+	if err := yaml.Unmarshal(b, file); err != nil {
+		return nil, fmt.Errorf("failed to parse config %v: %v", path, err)
+	}
+	for name := range file.Languages {
+		if _, ok := gen.FindTarget(name); !ok {
+			return nil, fmt.Errorf("unknown target %q in config %v", name, path)
+		}
+	}
+	if _, err := gen.LintSeverities(file.Lint); err != nil {
+		return nil, fmt.Errorf("%v in config %v", err, path)
+	}
+	if err := file.check(); err != nil {
+		return nil, fmt.Errorf("%v in config %v", err, path)
+	}
+	for name, l := range file.Languages {
+		if err := l.check(); err != nil {
+			return nil, fmt.Errorf("%v for %v in config %v", err, name, path)
+		}
+	}
+	return file, nil
+}
+
+// check validates the settings taking one of a list of values
+func (c languageConfig) check() error {
+	if err := checkValue("int64", c.Int64, gen.Int64Types); err != nil {
+		return err
+	}
+	return checkValue("optional", c.Optional, gen.OptionalTypes)
+}
+
+// checkValue fails when a setting isn't empty or one of values
+func checkValue(name, value string, values []string) error {
+	if value == "" {
+		return nil
+	}
+	for _, v := range values {
+		if value == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %v %q, use %v", name, value, strings.Join(values, ", "))
+}
+
+// merge returns c with the settings set in o
+func (c languageConfig) merge(o languageConfig) languageConfig {
+	if o.TokenEnv != "" {
+		c.TokenEnv = o.TokenEnv
+	}
+	if o.APIURL != "" {
+		c.APIURL = o.APIURL
+	}
+	if o.ImportPath != "" {
+		c.ImportPath = o.ImportPath
+	}
+	if o.Output != "" {
+		c.Output = o.Output
+	}
+	if o.ImportNames != nil {
+		names := map[string]string{}
+		for k, v := range c.ImportNames {
+			names[k] = v
+		}
+		for k, v := range o.ImportNames {
+			names[k] = v
+		}
+		c.ImportNames = names
+	}
+	if o.Int64 != "" {
+		c.Int64 = o.Int64
+	}
+	if o.Optional != "" {
+		c.Optional = o.Optional
+	}
+	return c
+}
+
+// target resolves the config of a target, the settings of the file apply on
+// top of the defaults of the target
+func (c *config) target(name string) gen.Config {
+	def := gen.DefaultConfig(name)
+	l := languageConfig{
+		TokenEnv:    def.TokenEnv,
+		APIURL:      def.APIURL,
+		ImportPath:  def.ImportPath,
+		ImportNames: def.ImportNames,
+	}
+	l = l.merge(c.languageConfig).merge(c.Languages[name])
+	return gen.Config{
+		TokenEnv:    l.TokenEnv,
+		APIURL:      strings.TrimSuffix(l.APIURL, "/"),
+		ImportPath:  strings.TrimSuffix(l.ImportPath, "/"),
+		Output:      l.Output,
+		ImportNames: l.ImportNames,
+		Int64:       l.Int64,
+		Optional:    l.Optional,
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// number of unchanged lines shown around the changes of a unified diff
+const diffContext = 3
+
+type diffOp struct {
+	// one of ' ', '-' or '+'
+	kind byte
+	line string
+}
+
+// splitLines splits text into lines, keeping the line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes the shortest edit script turning a into b using the
+// Myers algorithm
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	// v holds the furthest x reached on every diagonal k, offset by max
+	v := make([]int, 2*max+2)
+	trace := [][]int{}
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int{}, v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// walk the trace backwards to recover the edits
+	ops := []diffOp{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// unifiedDiff writes the differences between two versions of a file in the
+// unified format, nothing is written if they are the same
+func unifiedDiff(w io.Writer, fromName, toName, from, to string) {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	// find the ranges of ops to print, changes closer than twice the context
+	// end up in the same hunk
+	type hunk struct{ start, end int }
+	hunks := []hunk{}
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := i-diffContext, i+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunk{start, end})
+	}
+	if len(hunks) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "--- %v\n+++ %v\n", fromName, toName)
+	// number of lines of each version before the current op
+	aLine, bLine, pos := 0, 0, 0
+	for _, h := range hunks {
+		for ; pos < h.start; pos++ {
+			aLine++
+			bLine++
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[h.start:h.end] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		aStart, bStart := aLine, bLine
+		if aLen > 0 {
+			aStart++
+		}
+		if bLen > 0 {
+			bStart++
+		}
+		fmt.Fprintf(w, "@@ -%v,%v +%v,%v @@\n", aStart, aLen, bStart, bLen)
+		for _, op := range ops[h.start:h.end] {
+			line := op.line
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n\\ No newline at end of file\n"
+			}
+			fmt.Fprintf(w, "%c%v", op.kind, line)
+		}
+		aLine += aLen
+		bLine += bLen
+		pos = h.end
+	}
+}
+
+const (
+	fileNew       = "new"
+	fileChanged   = "changed"
+	fileUnchanged = "unchanged"
+)
+
+// compare reports whether a rendered file is new, changed or unchanged
+// compared to the file on disk, along with the content on disk
+func compare(path string, rendered []byte) (string, []byte, error) {
+	current, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fileNew, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if bytes.Equal(current, rendered) {
+		return fileUnchanged, current, nil
+	}
+	return fileChanged, current, nil
+}
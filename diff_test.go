@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// numbered returns the lines "1\n" to "n\n", with line i replaced by
+// changed[i] if set
+func numbered(n int, changed map[int]string) string {
+	s := ""
+	for i := 1; i <= n; i++ {
+		if line, ok := changed[i]; ok {
+			s += line
+			continue
+		}
+		s += strconv.Itoa(i) + "\n"
+	}
+	return s
+}
+
+var diffTests = []struct {
+	name     string
+	from, to string
+	want     string
+}{
+	{
+		name: "same",
+		from: "a\nb\n",
+		to:   "a\nb\n",
+		want: "",
+	},
+	{
+		name: "both empty",
+		want: "",
+	},
+	{
+		name: "from empty",
+		to:   "a\nb\n",
+		want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+	},
+	{
+		name: "to empty",
+		from: "a\nb\n",
+		want: "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+	},
+	{
+		name: "changed line",
+		from: "a\nb\nc\n",
+		to:   "a\nx\nc\n",
+		want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+	},
+	{
+		name: "context",
+		from: numbered(10, nil),
+		to:   numbered(10, map[int]string{5: "x\n"}),
+		want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+	},
+	{
+		name: "inserted line",
+		from: "a\nb\n",
+		to:   "a\nx\nb\n",
+		want: "--- a\n+++ b\n@@ -1,2 +1,3 @@\n a\n+x\n b\n",
+	},
+	{
+		name: "no newline at end of from",
+		from: "a\nb",
+		to:   "a\nb\n",
+		want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+	},
+	{
+		name: "no newline at end of to",
+		from: "a\nb\n",
+		to:   "a\nc",
+		want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n\\ No newline at end of file\n",
+	},
+	{
+		name: "merged hunks",
+		from: numbered(12, nil),
+		to:   numbered(12, map[int]string{2: "x\n", 8: "y\n"}),
+		want: "--- a\n+++ b\n@@ -1,11 +1,11 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n 9\n 10\n 11\n",
+	},
+	{
+		name: "separate hunks",
+		from: numbered(12, nil),
+		to:   numbered(12, map[int]string{1: "x\n", 10: "y\n"}),
+		want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n@@ -7,6 +7,6 @@\n 7\n 8\n 9\n-10\n+y\n 11\n 12\n",
+	},
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, tt := range diffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			unifiedDiff(&b, "a", "b", tt.from, tt.to)
+			if b.String() != tt.want {
+				t.Errorf("got\n%v\nwant\n%v", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestUnifiedDiffPatch applies the diffs back to the files they were made
+// from, like patch would
+func TestUnifiedDiffPatch(t *testing.T) {
+	for _, tt := range diffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			unifiedDiff(&b, "a", "b", tt.from, tt.to)
+			got, err := applyDiff(tt.from, b.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.to {
+				t.Errorf("got %q, want %q", got, tt.to)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		// length of the shortest edit script
+		edits int
+	}{
+		{"", "", 0},
+		{"", "a\n", 1},
+		{"a\n", "", 1},
+		{"a\nb\nc\n", "a\nb\nc\n", 0},
+		{"a\nb\nc\na\nb\nb\na\n", "c\nb\na\nb\na\nc\n", 5},
+		{"a\nb\nc\n", "c\nb\na\n", 4},
+		{"a\nb\n", "b\na\n", 2},
+	} {
+		a, b := splitLines(tt.a), splitLines(tt.b)
+		ops := diffLines(a, b)
+		edits := 0
+		from, to := "", ""
+		for _, op := range ops {
+			if op.kind != ' ' {
+				edits++
+			}
+			if op.kind != '+' {
+				from += op.line
+			}
+			if op.kind != '-' {
+				to += op.line
+			}
+		}
+		if edits != tt.edits {
+			t.Errorf("%q -> %q: got %v edits, want %v", tt.a, tt.b, edits, tt.edits)
+		}
+		if from != tt.a || to != tt.b {
+			t.Errorf("%q -> %q: ops give %q -> %q", tt.a, tt.b, from, to)
+		}
+	}
+}
+
+// applyDiff applies a unified diff of a single file to from
+func applyDiff(from, diff string) (string, error) {
+	a := splitLines(from)
+	out := []string{}
+	lines := splitLines(diff)
+	// index in a of the next line to copy
+	next := 0
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+			continue
+		}
+		if strings.HasPrefix(line, "@@ ") {
+			var aStart, aLen, bStart, bLen int
+			if _, err := fmt.Sscanf(line, "@@ -%d,%d +%d,%d @@", &aStart, &aLen, &bStart, &bLen); err != nil {
+				return "", fmt.Errorf("bad hunk header %q: %v", line, err)
+			}
+			// an empty range starts after its line
+			if aLen > 0 {
+				aStart--
+			}
+			for ; next < aStart; next++ {
+				out = append(out, a[next])
+			}
+			continue
+		}
+		text := line[1:]
+		if i+1 < len(lines) && lines[i+1] == "\\ No newline at end of file\n" {
+			text = strings.TrimSuffix(text, "\n")
+			i++
+		}
+		switch line[0] {
+		case ' ', '-':
+			if next >= len(a) || a[next] != text {
+				return "", fmt.Errorf("line %v doesn't match %q", next+1, text)
+			}
+			if line[0] == ' ' {
+				out = append(out, text)
+			}
+			next++
+		case '+':
+			out = append(out, text)
+		default:
+			return "", fmt.Errorf("bad line %q", line)
+		}
+	}
+	return strings.Join(append(out, a[next:]...), ""), nil
+}
@@ -0,0 +1,201 @@
+package gen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// templates are the templates of all the targets, a change to any of them
+// invalidates the cache
+var templates = []string{
+	goIndexTemplate, goServiceTemplate, goWellKnownTemplate, goEnumTemplate, goOneofTemplate, goInt64Template, goExampleTemplate, goExampleHelpersTemplate, goReadmeTopTemplate, goReadmeBottomTemplate,
+	tsIndexTemplate, tsServiceTemplate, tsConvertTemplate, tsEnumTemplate, tsOneofTemplate, tsExampleTemplate, tsReadmeTopTemplate, tsReadmeBottomTemplate,
+	dartServiceTemplate, dartWellKnownTemplate, dartEnumTemplate, dartOneofTemplate, dartExampleTemplate, dartReadmeTopTemplate, dartReadmeBottomTemplate,
+	curlExampleTemplate, cliExampleTemplate,
+}
+
+// Cache remembers a hash of the inputs of the services generated, so the
+// services whose spec, proto and examples didn't change since the last run
+// can be skipped. Services are keyed by target, so one cache can be shared
+// by the runs of several targets. It's safe for concurrent use.
+type Cache struct {
+	path string
+	// the version of the generator is part of every hash
+	version string
+
+	mu       sync.Mutex
+	Services map[string]string `json:"services"`
+}
+
+// LoadCache reads the cache at path, a missing file is an empty cache
+func LoadCache(path, version string) (*Cache, error) {
+	c := &Cache{
+		path:     path,
+		version:  version,
+		Services: map[string]string{},
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache %v: %v", path, err)
+	}
+	if c.Services == nil {
+		c.Services = map[string]string{}
+	}
+	return c, nil
+}
+
+// Save writes the cache back to the file it was loaded from
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, append(b, '\n'), FILE_EXECUTE_PERMISSION)
+}
+
+func (c *Cache) get(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Services[key]
+}
+
+func (c *Cache) set(key, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hash == "" {
+		delete(c.Services, key)
+		return
+	}
+	c.Services[key] = hash
+}
+
+// inputsHash hashes everything the output of a service depends on: its
+// spec, proto and examples files, the options and config of the run, the
+// templates and the version of the generator. An empty hash is returned
+// when the spec comes from opts.LoadSpec, which can't be hashed.
+func (c *Cache) inputsHash(serviceName string, opts Options) (string, error) {
+	if opts.LoadSpec != nil {
+		return "", nil
+	}
+	h := sha256.New()
+	cfg, err := json.Marshal(opts.Config)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "version=%v\ntarget=%v\nconfig=%s\n", c.version, opts.Target, cfg)
+	fmt.Fprintf(h, "client=%v\nexamples=%v\n", opts.ClientPath, opts.ExamplesPath)
+	fmt.Fprintf(h, "nomake=%v\nstrict=%v\nproto=%v\nspecdir=%v\nprotopaths=%v\n", opts.NoMake, opts.Strict, opts.FromProto, opts.SpecDir, opts.ProtoPaths)
+	for _, t := range templates {
+		fmt.Fprintf(h, "%v\x00", t)
+	}
+
+	files, err := InputFiles(serviceName, opts)
+	if err != nil {
+		return "", err
+	}
+	filesHash, err := HashInputs(files)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "files=%v\n", filesHash)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashInputs hashes the paths and contents of the input files of a service,
+// see InputFiles. Missing files are left out.
+func HashInputs(files []string) (string, error) {
+	h := sha256.New()
+	for _, path := range files {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%v\x00", path)
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprint(h, "\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// InputFiles returns the sorted paths of the files a service is generated
+// from: its api json, protos, the protos of opts.ProtoPaths and examples.json,
+// some of which may not exist
+func InputFiles(serviceName string, opts Options) ([]string, error) {
+	serviceDir := filepath.Join(opts.Root, serviceName)
+	files := []string{
+		filepath.Join(serviceDir, "examples.json"),
+		filepath.Join(serviceDir, "config", "examples.json"),
+	}
+	if opts.SpecDir != "" {
+		files = append(files,
+			filepath.Join(opts.SpecDir, serviceName+".json"),
+			filepath.Join(opts.SpecDir, serviceName+".proto"))
+	}
+	// the api json files, see apiSpec
+	serviceFiles, err := ioutil.ReadDir(serviceDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range serviceFiles {
+		if strings.Contains(f.Name(), "api") && strings.Contains(f.Name(), "-") && strings.HasSuffix(f.Name(), ".json") {
+			files = append(files, filepath.Join(serviceDir, f.Name()))
+		}
+	}
+	// the protos "make api" builds the spec from
+	err = filepath.Walk(filepath.Join(serviceDir, "proto"), func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the protos the service protos can import, shared by all the services
+	for _, dir := range opts.ProtoPaths {
+		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil || info.IsDir() || !strings.HasSuffix(path, ".proto") {
+				return err
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -1,14 +1,11 @@
-package main
+package gen
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"text/template"
 
 	"github.com/fatih/camelcase"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -16,74 +13,62 @@ import (
 )
 
 type cliG struct {
-	generator
-	// add appropriate fields
+	Generator
+	out Output
+	cfg Config
 }
 
 // We implement an empty methods (except for ExampleAndReadmeEdit) in order to satisfy
 // the generator interface.
-func (c *cliG) ServiceClient(serviceName, dartPath string, service service) {
+func (c *cliG) ServiceClient(serviceName, dartPath string, service Service) error {
+	return nil
 }
 
-func (c *cliG) schemaToType(serviceName, typeName string, schemas map[string]*openapi3.SchemaRef) string {
-	return ""
+func (c *cliG) schemaToType(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+	return "", nil
 }
 
-func (c *cliG) IndexFile(dartPath string, services []service) {
+func (c *cliG) IndexFile(dartPath string, services []Service) error {
+	return nil
 }
 
-func (c *cliG) TopReadme(serviceName, examplesPath string, service service) {
+func (c *cliG) TopReadme(serviceName, examplesPath string, service Service) error {
+	return nil
 }
 
-func (c *cliG) ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service service, example example) {
-	// curl example
-	templ, err := template.New("cli" + serviceName + endpoint).Funcs(funcMap()).Parse(cliExampleTemplate)
-	if err != nil {
-		fmt.Println("Failed to unmarshal", err)
-		os.Exit(1)
-	}
-	b := bytes.Buffer{}
-	buf := bufio.NewWriter(&b)
+func (c *cliG) ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service Service, example Example) error {
+	// cli example
+	cliExampleFile := filepath.Join(examplesPath, "cli", serviceName, endpoint, title+".sh")
 
 	command := strings.Join(camelcase.Split(endpoint), " ")
-
-	err = templ.Execute(buf, map[string]interface{}{
+	b, err := render("cli"+serviceName+endpoint, cliExampleTemplate, map[string]interface{}{
 		"service":  service,
+		"config":   c.cfg,
 		"example":  example,
 		"endpoint": endpoint,
 		"command":  strings.ToLower(command),
 		"funcName": strcase.UpperCamelCase(title),
 	})
-
 	if err != nil {
-		fmt.Println("Failed to apply a parsed template to the specified data object", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to render %v: %v", cliExampleFile, err)
 	}
-
-	err = os.MkdirAll(filepath.Join(examplesPath, "cli", serviceName, endpoint), FOLDER_EXECUTE_PERMISSION)
+	err = c.out.WriteFile(cliExampleFile, b)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
-
-	cliExampleFile := filepath.Join(examplesPath, "cli", serviceName, endpoint, title+".sh")
-	f, err := os.OpenFile(cliExampleFile, os.O_TRUNC|os.O_WRONLY|os.O_CREATE, FILE_EXECUTE_PERMISSION)
-	if err != nil {
-		fmt.Println("Failed to open schema file", err)
-		os.Exit(1)
-	}
-
-	buf.Flush()
-	_, err = f.Write(b.Bytes())
-	if err != nil {
-		fmt.Println("Failed to append to schema file", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to write file: %v", err)
 	}
+	return nil
 }
 
 func schemaToCLIExample(exampleJSON map[string]interface{}) string {
+	keys := []string{}
+	for key := range exampleJSON {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	s := ""
-	for key, value := range exampleJSON {
+	for _, key := range keys {
+		value := exampleJSON[key]
 		switch value.(type) {
 		case float64:
 			val := value.(float64)
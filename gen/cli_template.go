@@ -1,4 +1,4 @@
-package main
+package gen
 
 const cliExampleTemplate = `{{ $reqType := requestType .endpoint }}{{ $service := .service -}}
 m3o {{ $service.Name }} {{ .command }} {{ cliExampleRequest .example.Request }}`
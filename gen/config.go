@@ -0,0 +1,60 @@
+package gen
+
+import "strings"
+
+// Config customises the generated code for a project other than m3o.com
+type Config struct {
+	// name of the env var the examples read the api token from
+	TokenEnv string
+	// url of the api gateway called by the examples
+	APIURL string
+	// import path or package of the clients, e.g. go.m3o.com
+	ImportPath string
+	// folder the clients are written to, relative to the root folder unless absolute
+	Output string
+	// names used to import services, e.g. when a service name is a keyword
+	ImportNames map[string]string
+	// TypeScript type of the 64 bit integers, see Int64Types
+	Int64 string
+	// Go type of the proto3 optional fields, see OptionalTypes
+	Optional string
+}
+
+// Int64Types are the TypeScript types 64 bit integers can be generated as,
+// number is the default and loses precision above 2^53
+var Int64Types = []string{"number", "string", "bigint"}
+
+// OptionalTypes are the Go types proto3 optional fields can be generated as,
+// value is the default and can't send zero values as they're omitted
+var OptionalTypes = []string{"value", "pointer"}
+
+// DefaultConfig returns the config of a target generating for m3o.com
+func DefaultConfig(target string) Config {
+	importPaths := map[string]string{
+		"go":   "go.m3o.com",
+		"ts":   "m3o",
+		"dart": "package:m3o",
+	}
+	return Config{
+		TokenEnv:   "M3O_API_TOKEN",
+		APIURL:     "https://api.m3o.com",
+		ImportPath: importPaths[target],
+		ImportNames: map[string]string{
+			"function": "fx",
+		},
+	}
+}
+
+// WebsocketURL returns the api url with a websocket scheme, used for streams
+func (c Config) WebsocketURL() string {
+	u := strings.Replace(c.APIURL, "https://", "wss://", 1)
+	return strings.Replace(u, "http://", "ws://", 1)
+}
+
+// ImportName returns the name a service is imported as
+func (c Config) ImportName(serviceName string) string {
+	if name, ok := c.ImportNames[serviceName]; ok {
+		return name
+	}
+	return serviceName
+}
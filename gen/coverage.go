@@ -0,0 +1,200 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stoewer/go-strcase"
+)
+
+// Coverage reports which endpoints of the services have examples
+type Coverage struct {
+	// totals over all the services
+	Endpoints int                `json:"endpoints"`
+	Covered   int                `json:"covered"`
+	Services  []*ServiceCoverage `json:"services"`
+}
+
+// ServiceCoverage reports which endpoints of a service have examples. The
+// endpoints are named like the keys of examples.json, e.g. "create".
+type ServiceCoverage struct {
+	Service   string   `json:"service"`
+	Endpoints []string `json:"endpoints"`
+	// endpoints without any example
+	Missing []string `json:"missing"`
+	// streaming endpoints without any example, also listed in Missing
+	MissingStreams []string      `json:"missing_streams"`
+	RunCheck       []ExampleName `json:"run_check"`
+	Idempotent     []ExampleName `json:"idempotent"`
+}
+
+// ExampleName identifies an example by endpoint and title
+type ExampleName struct {
+	Endpoint string `json:"endpoint"`
+	Title    string `json:"title"`
+}
+
+// ExampleCoverage reports which endpoints of the spec of a service have
+// examples, examples for endpoints not in the spec are left out
+func ExampleCoverage(serviceName string, spec *openapi3.Swagger, examples map[string][]Example) *ServiceCoverage {
+	sc := &ServiceCoverage{
+		Service:        serviceName,
+		Endpoints:      []string{},
+		Missing:        []string{},
+		MissingStreams: []string{},
+		RunCheck:       []ExampleName{},
+		Idempotent:     []ExampleName{},
+	}
+	// examples.json keys are matched case insensitively like the paths
+	byEndpoint := map[string][]Example{}
+	for endpoint, exs := range examples {
+		byEndpoint[strings.ToLower(endpoint)] = append(byEndpoint[strings.ToLower(endpoint)], exs...)
+	}
+
+	streams := map[string]bool{}
+	for path, item := range spec.Paths {
+		// eg. "/notes/Notes/Events"
+		endpoint := strcase.LowerCamelCase(path[strings.LastIndex(path, "/")+1:])
+		sc.Endpoints = append(sc.Endpoints, endpoint)
+		if item.Post != nil {
+			if _, ok := item.Post.Responses["stream"]; ok {
+				streams[endpoint] = true
+			}
+		}
+	}
+	sort.Strings(sc.Endpoints)
+
+	for _, endpoint := range sc.Endpoints {
+		exs := byEndpoint[strings.ToLower(endpoint)]
+		if len(exs) == 0 {
+			sc.Missing = append(sc.Missing, endpoint)
+			if streams[endpoint] {
+				sc.MissingStreams = append(sc.MissingStreams, endpoint)
+			}
+			continue
+		}
+		for _, ex := range exs {
+			if ex.RunCheck {
+				sc.RunCheck = append(sc.RunCheck, ExampleName{Endpoint: endpoint, Title: ex.Title})
+			}
+			if ex.Idempotent {
+				sc.Idempotent = append(sc.Idempotent, ExampleName{Endpoint: endpoint, Title: ex.Title})
+			}
+		}
+	}
+	return sc
+}
+
+// Add adds the coverage of a service, keeping the services sorted
+func (c *Coverage) Add(sc *ServiceCoverage) {
+	c.Services = append(c.Services, sc)
+	sort.Slice(c.Services, func(i, j int) bool {
+		return c.Services[i].Service < c.Services[j].Service
+	})
+	c.Endpoints += len(sc.Endpoints)
+	c.Covered += len(sc.Endpoints) - len(sc.Missing)
+}
+
+// LoadCoverage reads a coverage report written as JSON
+func LoadCoverage(path string) (*Coverage, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Coverage{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal coverage report %v: %v", path, err)
+	}
+	return c, nil
+}
+
+// JSON renders the report as indented JSON
+func (c *Coverage) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Markdown renders the report as a summary table followed by the details
+// of every service
+func (c *Coverage) Markdown() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Example coverage\n\n")
+	fmt.Fprintf(&b, "%v of %v endpoints have examples (%v).\n\n", c.Covered, c.Endpoints, percent(c.Covered, c.Endpoints))
+	fmt.Fprintf(&b, "| Service | Endpoints | With examples | Coverage |\n")
+	fmt.Fprintf(&b, "| --- | ---: | ---: | ---: |\n")
+	for _, sc := range c.Services {
+		covered := len(sc.Endpoints) - len(sc.Missing)
+		fmt.Fprintf(&b, "| %v | %v | %v | %v |\n", sc.Service, len(sc.Endpoints), covered, percent(covered, len(sc.Endpoints)))
+	}
+
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%v:\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %v\n", item)
+		}
+	}
+	names := func(exs []ExampleName) []string {
+		ret := []string{}
+		for _, ex := range exs {
+			ret = append(ret, fmt.Sprintf("`%v` %v", ex.Endpoint, ex.Title))
+		}
+		return ret
+	}
+	quote := func(endpoints []string) []string {
+		ret := []string{}
+		for _, e := range endpoints {
+			ret = append(ret, "`"+e+"`")
+		}
+		return ret
+	}
+	for _, sc := range c.Services {
+		fmt.Fprintf(&b, "\n## %v\n", sc.Service)
+		if len(sc.Missing) == 0 {
+			fmt.Fprintf(&b, "\nEvery endpoint has examples.\n")
+		}
+		list("Endpoints without examples", quote(sc.Missing))
+		list("Streaming endpoints without examples", quote(sc.MissingStreams))
+		list("Examples marked run_check", names(sc.RunCheck))
+		list("Examples marked idempotent", names(sc.Idempotent))
+	}
+	return b.Bytes()
+}
+
+// NewUncovered returns the endpoints without examples, as
+// "<service>/<endpoint>", that aren't in the baseline report. Use it to
+// require examples for every endpoint added since the baseline.
+func (c *Coverage) NewUncovered(baseline *Coverage) []string {
+	known := map[string]bool{}
+	for _, sc := range baseline.Services {
+		for _, endpoint := range sc.Endpoints {
+			known[sc.Service+"/"+endpoint] = true
+		}
+	}
+	ret := []string{}
+	for _, sc := range c.Services {
+		for _, endpoint := range sc.Missing {
+			if !known[sc.Service+"/"+endpoint] {
+				ret = append(ret, sc.Service+"/"+endpoint)
+			}
+		}
+	}
+	return ret
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", float64(n)*100/float64(total))
+}
@@ -0,0 +1,53 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stoewer/go-strcase"
+)
+
+type shellG struct {
+	Generator
+	out Output
+	cfg Config
+}
+
+// We implement an empty methods (except for ExampleAndReadmeEdit) in order to satisfy
+// the generator interface.
+func (s *shellG) ServiceClient(serviceName, dartPath string, service Service) error {
+	return nil
+}
+
+func (s *shellG) schemaToType(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+	return "", nil
+}
+
+func (s *shellG) IndexFile(dartPath string, services []Service) error {
+	return nil
+}
+
+func (s *shellG) TopReadme(serviceName, examplesPath string, service Service) error {
+	return nil
+}
+
+func (s *shellG) ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service Service, example Example) error {
+	// curl example
+	curlExampleFile := filepath.Join(examplesPath, "curl", serviceName, endpoint, title+".sh")
+	b, err := render("curl"+serviceName+endpoint, curlExampleTemplate, map[string]interface{}{
+		"service":  service,
+		"config":   s.cfg,
+		"example":  example,
+		"endpoint": endpoint,
+		"funcName": strcase.UpperCamelCase(title),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", curlExampleFile, err)
+	}
+	err = s.out.WriteFile(curlExampleFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,543 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stoewer/go-strcase"
+	dpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type dartG struct {
+	Generator
+	out Output
+	cfg Config
+}
+
+func (d *dartG) ServiceClient(serviceName, dartPath string, service Service) error {
+	clientFile := filepath.Join(dartPath, "lib", "src", serviceName, fmt.Sprint(serviceName, ".dart"))
+	b, err := render("dart"+serviceName, dartServiceTemplate, map[string]interface{}{
+		"service": service,
+		"config":  d.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", clientFile, err)
+	}
+	err = d.out.WriteFile(clientFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (d *dartG) schemaToType(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+	var jsonInt64 = `
+	@JsonKey(fromJson: int64FromString, toJson: int64ToString)
+	{{ .type }}? {{ .parameter }}
+	`
+	var normalType = `{{ .type }}? {{ .parameter }}`
+	var arrayType = `List<{{ .type }}>? {{ .parameter }}`
+	var mapType = `Map<{{ .type1 }}, {{ .type2 }}>? {{ .parameter }}`
+	var anyType = `dynamic {{ .parameter }}`
+	var stringType = "String"
+	var int64Type = "int"
+	var doubleType = "double"
+	var boolType = "bool"
+
+	runTemplate := func(tmpName, temp string, payload map[string]interface{}) string {
+		t, err := template.New(tmpName).Parse(temp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to parse %s - err: %v\n", temp, err)
+			return ""
+		}
+		var tb bytes.Buffer
+		err = t.Execute(&tb, payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "faild to apply parsed template %s to payload %v - err: %v\n", temp, payload, err)
+			return ""
+		}
+
+		return tb.String()
+	}
+
+	typesMapper := func(t string) string {
+		switch t {
+		case "STRING":
+			return stringType
+		case "INT32", "INT64", "SINT32", "SINT64", "SFIXED32", "SFIXED64",
+			"UINT32", "UINT64", "FIXED32", "FIXED64":
+			return int64Type
+		case "DOUBLE", "FLOAT":
+			return doubleType
+		case "BOOL":
+			return boolType
+		default:
+			if wkt, ok := dartWellKnownTypes[t]; ok {
+				return wkt
+			}
+			return t
+		}
+	}
+
+	output := []string{}
+	protoMessage := schemas[typeName]
+	if protoMessage == nil || protoMessage.Value == nil {
+		return "", fmt.Errorf("can't find %v in the spec", typeName)
+	}
+
+	// return an empty string if there is no properties for the typeName
+	if len(protoMessage.Value.Properties) == 0 {
+		return "", nil
+	}
+
+	for _, p := range propertyNames(service, typeName, protoMessage.Value.Properties) {
+		meta := protoMessage.Value.Properties[p]
+		comments := ""
+		o := ""
+
+		if meta.Value.Description != "" {
+			for _, commentLine := range strings.Split(meta.Value.Description, "\n") {
+				comments += "/// " + strings.TrimSpace(commentLine) + "\n"
+			}
+		}
+
+		// lists and maps of 64 bit integers encode them as strings, like
+		// int64FromString does for single ones
+		if kind, key := int64Values(service, typeName, p); kind != "" {
+			if key == "" {
+				o = runTemplate("array", arrayType, map[string]interface{}{
+					"type":      int64Type,
+					"parameter": p,
+				})
+			} else {
+				o = runTemplate("map", mapType, map[string]interface{}{
+					"type1":     typesMapper(key),
+					"type2":     int64Type,
+					"parameter": p,
+				})
+			}
+			converter := dartInt64Converter(key)
+			o = fmt.Sprintf("@JsonKey(fromJson: _%vFromJson, toJson: _%vToJson) %v", converter, converter, o)
+			output = append(output, comments+o)
+			continue
+		}
+
+		// well-known types are mapped to native types, whatever the spec says,
+		// with converters for the ones json_serializable doesn't encode right
+		if wkt, repeated, isMap := wellKnownType(service, typeName, p); wkt != "" {
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			payload := map[string]interface{}{
+				"type":      typesMapper(types[len(types)-1]),
+				"parameter": p,
+			}
+			converter, shape := dartConverters[wkt], ""
+			switch {
+			case isMap:
+				payload["type1"], payload["type2"] = typesMapper(types[0]), payload["type"]
+				o, shape = runTemplate("map", mapType, payload), "Map"
+				// the converters of map values only handle string keys
+				if types[0] != "STRING" {
+					converter = ""
+				}
+			case repeated:
+				o, shape = runTemplate("array", arrayType, payload), "List"
+			case wkt == "VALUE":
+				o = runTemplate("any", anyType, payload)
+			case wkt == "INT64VALUE" || wkt == "UINT64VALUE":
+				o = runTemplate("jsonInt64", jsonInt64, payload)
+			default:
+				o = runTemplate("normal", normalType, payload)
+			}
+			if converter != "" {
+				o = fmt.Sprintf("@JsonKey(fromJson: _%v%vFromJson, toJson: _%v%vToJson) %v", converter, shape, converter, shape, o)
+			}
+			output = append(output, comments+o)
+			continue
+		}
+		if enum, repeated := enumField(service, typeName, p); enum != "" && !repeated {
+			o = runTemplate("normal", normalType, map[string]interface{}{
+				"type":      enum,
+				"parameter": p,
+			})
+			output = append(output, comments+o)
+			continue
+		}
+
+		switch meta.Value.Type {
+		case "string":
+			payload := map[string]interface{}{
+				"type":      stringType,
+				"parameter": p,
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "boolean":
+			payload := map[string]interface{}{
+				"type":      boolType,
+				"parameter": p,
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "number":
+			switch meta.Value.Format {
+			case "int32", "uint32":
+				payload := map[string]interface{}{
+					"type":      int64Type,
+					"parameter": p,
+				}
+				o = runTemplate("normal", normalType, payload)
+			case "int64", "uint64":
+				payload := map[string]interface{}{
+					"type":      int64Type,
+					"parameter": p,
+				}
+				o = runTemplate("jsonInt64", jsonInt64, payload)
+			case "float", "double":
+				payload := map[string]interface{}{
+					"type":      doubleType,
+					"parameter": p,
+				}
+				o = runTemplate("normal", normalType, payload)
+			}
+		case "array":
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			payload := map[string]interface{}{
+				"type":      typesMapper(types[0]),
+				"parameter": p,
+			}
+			o = runTemplate("array", arrayType, payload)
+		case "object":
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			if len(types) == 1 {
+				// a Message Type
+				payload := map[string]interface{}{
+					"type":      typesMapper(types[0]),
+					"parameter": p,
+				}
+				o = runTemplate("normal", normalType, payload)
+			} else {
+				// a Map object
+				payload := map[string]interface{}{
+					"type1":     typesMapper(types[0]),
+					"type2":     typesMapper(types[1]),
+					"parameter": p,
+				}
+				o = runTemplate("map", mapType, payload)
+			}
+		default:
+			payload := map[string]interface{}{
+				"parameter": p,
+			}
+			o = runTemplate("any", anyType, payload)
+		}
+
+		output = append(output, comments+o)
+	}
+
+	res := "{" + strings.Join(output, ", ") + ",}"
+	return res, nil
+}
+
+func (d *dartG) IndexFile(dartPath string, services []Service) error {
+	// 	templ, err := template.New("dartCollector").Funcs(funcMap()).Parse(dartIndexTemplate)
+	// 	if err != nil {
+	// 		fmt.Println("Failed to unmarshal", err)
+	// 		os.Exit(1)
+	// 	}
+	// 	b := bytes.Buffer{}
+	// 	buf := bufio.NewWriter(&b)
+	// 	err = templ.Execute(buf, map[string]interface{}{
+	// 		"services": services,
+	// 	})
+	// 	if err != nil {
+	// 		fmt.Println("Failed to unmarshal", err)
+	// 		os.Exit(1)
+	// 	}
+	// 	f, err := os.OpenFile(filepath.Join(dartPath, "lib", "m3o.dart"), os.O_TRUNC|os.O_WRONLY|os.O_CREATE, FILE_EXECUTE_PERMISSION)
+	// 	if err != nil {
+	// 		fmt.Println("Failed to open collector file", err)
+	// 		os.Exit(1)
+	// 	}
+	// 	buf.Flush()
+	// 	_, err = f.Write(b.Bytes())
+	// 	if err != nil {
+	// 		fmt.Println("Failed to append to collector file", err)
+	// 		os.Exit(1)
+	// 	}
+	return nil
+}
+
+func (d *dartG) TopReadme(serviceName, examplesPath string, service Service) error {
+	readme := filepath.Join(examplesPath, "dart", serviceName, "README.md")
+	b, err := render("dartTopReadme"+serviceName, dartReadmeTopTemplate, map[string]interface{}{
+		"service": service,
+		"config":  d.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", readme, err)
+	}
+	err = d.out.WriteFile(readme, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (d *dartG) ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service Service, example Example) error {
+	data := map[string]interface{}{
+		"service":  service,
+		"config":   d.cfg,
+		"example":  example,
+		"endpoint": endpoint,
+		"funcName": strcase.UpperCamelCase(title),
+	}
+
+	exampleFile := filepath.Join(examplesPath, "dart", serviceName, endpoint, title, "main.dart")
+	b, err := render("dart"+serviceName+endpoint, dartExampleTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", exampleFile, err)
+	}
+	err = d.out.WriteFile(exampleFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	if example.RunCheck && example.Idempotent {
+		err = d.out.WriteFile(filepath.Join(examplesPath, "dart", serviceName, endpoint, title, ".run"), []byte{})
+		if err != nil {
+			return fmt.Errorf("failed to write run file: %v", err)
+		}
+	}
+
+	// per endpoint dart readme examples
+	readmeAppend := filepath.Join(examplesPath, "dart", serviceName, "README.md")
+	b, err = render("dartReadmebottom"+serviceName+endpoint, dartReadmeBottomTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", readmeAppend, err)
+	}
+	err = d.out.AppendFile(readmeAppend, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func schemaToDartExample(exampleJSON map[string]interface{}) string {
+	isEmpty := len(exampleJSON) == 0
+	if !isEmpty {
+		bs, _ := json.MarshalIndent(exampleJSON, "", "  ")
+		exa := strings.Replace(string(bs), "}", ",}", 1)
+		exa = strings.ReplaceAll(exa, "$micro_verification_link", "\\$micro_verification_link")
+		return strings.ReplaceAll(exa, "$code", "\\$code")
+	}
+
+	return "{}"
+}
+
+// dartWellKnownTypes are the Dart types of single values of the well-known
+// types
+var dartWellKnownTypes = map[string]string{
+	"JSON":        "Map<String, dynamic>",
+	"ANY":         "Map<String, dynamic>",
+	"EMPTY":       "Map<String, dynamic>",
+	"VALUE":       "dynamic",
+	"LISTVALUE":   "List<dynamic>",
+	"TIMESTAMP":   "DateTime",
+	"DURATION":    "Duration",
+	"FIELDMASK":   "List<String>",
+	"DOUBLEVALUE": "double",
+	"FLOATVALUE":  "double",
+	"INT64VALUE":  "int",
+	"UINT64VALUE": "int",
+	"INT32VALUE":  "int",
+	"UINT32VALUE": "int",
+	"BOOLVALUE":   "bool",
+	"STRINGVALUE": "String",
+	// base64 encoded
+	"BYTESVALUE": "String",
+}
+
+// dartConverters are the prefixes of the JsonKey converters of the
+// well-known types json_serializable doesn't encode like protobuf does, see
+// dartWellKnownTemplate
+var dartConverters = map[string]string{
+	"TIMESTAMP": "timestamp",
+	"DURATION":  "duration",
+	"FIELDMASK": "fieldMask",
+}
+
+// dartWellKnownHelpers returns the JsonKey converters of the well-known
+// types used by a service and of its lists and maps of 64 bit integers
+func dartWellKnownHelpers(service Service) (string, error) {
+	b, err := render("dartWellKnown"+service.Name, dartWellKnownTemplate, map[string]interface{}{
+		"converters": dartConvertersUsed(service),
+	})
+	return string(b), err
+}
+
+// dartConvertersUsed returns the JsonKey converters used by the fields of a
+// service, by their prefix, see dartConverters and dartInt64Converter, and
+// with the List and Map suffixes for the ones of the repeated and map
+// fields of well-known types. The converters of the lists and maps use the
+// one of single values, which is listed too.
+func dartConvertersUsed(service Service) map[string]bool {
+	ret := map[string]bool{}
+	for _, m := range serviceMessages(service) {
+		for _, field := range m.GetFields() {
+			value, shape := field, ""
+			switch {
+			case field.IsMap():
+				// the converters of map values only handle string keys
+				if field.GetMapKeyType().GetType() != dpb.FieldDescriptorProto_TYPE_STRING {
+					continue
+				}
+				value, shape = field.GetMapValueType(), "Map"
+			case field.IsRepeated():
+				shape = "List"
+			}
+			if converter := dartConverters[wellKnownTypeOf(value)]; converter != "" {
+				ret[converter] = true
+				ret[converter+shape] = true
+			}
+		}
+	}
+	for kv := range usedInt64Values(service) {
+		ret["int64"] = true
+		ret[dartInt64Converter(kv[1])] = true
+	}
+	return ret
+}
+
+// dartInt64Converter returns the prefix of the JsonKey converter of a list
+// of 64 bit integers, or of a map of them with keys of the given type, see
+// dartWellKnownTemplate
+func dartInt64Converter(key string) string {
+	switch key {
+	case "":
+		return "int64List"
+	case "STRING":
+		return "int64Map"
+	case "BOOL":
+		return "int64BoolMap"
+	}
+	return "int64IntMap"
+}
+
+// dartKeywords are the reserved words of Dart enum values can't be named
+var dartKeywords = map[string]bool{
+	"assert": true, "break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "else": true, "enum": true, "extends": true,
+	"false": true, "final": true, "finally": true, "for": true, "if": true, "in": true,
+	"is": true, "new": true, "null": true, "rethrow": true, "return": true, "super": true,
+	"switch": true, "this": true, "throw": true, "true": true, "try": true, "var": true,
+	"void": true, "while": true, "with": true, "values": true, "index": true,
+}
+
+// dartEnums returns the declarations of the enums used by a service, with
+// the proto names of their values as JSON values
+func dartEnums(service Service) (string, error) {
+	enums := serviceEnums(service)
+	names := map[string]string{}
+	for _, e := range enums {
+		for _, v := range e.Values {
+			name := strcase.LowerCamelCase(v.Short)
+			if dartKeywords[name] {
+				name += "Value"
+			}
+			names[e.Name+"."+v.Name] = name
+		}
+	}
+	b, err := render("dartEnums"+service.Name, dartEnumTemplate, map[string]interface{}{
+		"enums": enums,
+		"names": names,
+	})
+	return string(b), err
+}
+
+// dartOneofAsserts returns the asserts checking at most one field of each
+// oneof of a type is set
+func dartOneofAsserts(service Service, typeName string) string {
+	ret := ""
+	for _, oo := range messageOneofs(service, typeName) {
+		// asserts of const constructors must be constant expressions
+		set := []string{}
+		for _, f := range oo.Fields {
+			set = append(set, fmt.Sprintf("(%v == null ? 0 : 1)", f))
+		}
+		ret += fmt.Sprintf("@Assert('%v <= 1', 'only one of %v may be set')\n\t", strings.Join(set, " + "), oo.names(func(f string) string { return f }))
+	}
+	return ret
+}
+
+// dartOneofs returns a union per oneof of a type, with the field set as
+// its value, and an extension on the type returning it
+func dartOneofs(service Service, typeName string) (string, error) {
+	oneofs := []map[string]interface{}{}
+	for _, oo := range messageOneofs(service, typeName) {
+		name := strings.Title(typeName) + strcase.UpperCamelCase(oo.Name)
+		variants := []map[string]string{}
+		for _, field := range oo.fields {
+			variants = append(variants, map[string]string{
+				"field":       field.GetName(),
+				"constructor": strcase.LowerCamelCase(field.GetName()),
+				"class":       name + strcase.UpperCamelCase(field.GetName()),
+				"type":        dartType(field),
+			})
+		}
+		oneofs = append(oneofs, map[string]interface{}{
+			"name":     name,
+			"field":    oo.Name,
+			"comment":  oo.Comment,
+			"getter":   strcase.LowerCamelCase(oo.Name),
+			"variants": variants,
+		})
+	}
+	if len(oneofs) == 0 {
+		return "", nil
+	}
+	// the fields of responses are on their data variant
+	on := strings.Title(typeName)
+	if strings.HasSuffix(typeName, "Response") {
+		on += "Data"
+	}
+	b, err := render("dartOneofs"+service.Name+typeName, dartOneofTemplate, map[string]interface{}{
+		"type":   strings.Title(typeName),
+		"on":     on,
+		"oneofs": oneofs,
+	})
+	return string(b), err
+}
+
+// dartType returns the Dart type of a single value of a field
+func dartType(field *desc.FieldDescriptor) string {
+	switch field.GetType() {
+	case dpb.FieldDescriptorProto_TYPE_MESSAGE, dpb.FieldDescriptorProto_TYPE_GROUP:
+		if wkt := wellKnownTypeOf(field); wkt != "" {
+			return dartWellKnownTypes[wkt]
+		}
+		return flatName(field.GetMessageType())
+	case dpb.FieldDescriptorProto_TYPE_ENUM:
+		return flatName(field.GetEnumType())
+	case dpb.FieldDescriptorProto_TYPE_STRING, dpb.FieldDescriptorProto_TYPE_BYTES:
+		return "String"
+	case dpb.FieldDescriptorProto_TYPE_BOOL:
+		return "bool"
+	case dpb.FieldDescriptorProto_TYPE_FLOAT, dpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return "double"
+	}
+	return "int"
+}
@@ -0,0 +1,282 @@
+package gen
+
+// const dartIndexTemplate = `library m3o;
+
+// export 'src/client.dart';
+// {{ range $service := .services }}export 'src/{{ $service.Name }}/{{ $service.Name}}.dart';
+// {{ end }}
+// `
+
+const dartServiceTemplate = `
+{{- $service := .service }}
+{{- if serviceHasStream $service.Spec $service.Name }}
+import 'dart:convert';
+{{- end }}
+import 'package:freezed_annotation/freezed_annotation.dart';
+import '../client/client.dart';
+
+part '{{ $service.Name }}.freezed.dart';
+part '{{ $service.Name }}.g.dart';
+
+class {{title $service.Name}}Service {
+	var _client;
+  	final String token;
+  
+	{{title $service.Name}}Service(String token) :token = token {
+	  _client = Client(token: token);
+	}
+{{ range $key, $req := $service.Spec.Components.RequestBodies }}{{ $reqType := requestType $key }}{{ $endpointName := requestTypeToEndpointName $key}}
+	/{{ if endpointComment $endpointName $service.Spec.Components.Schemas }}{{ endpointComment $endpointName $service.Spec.Components.Schemas }}{{ end -}}
+	{{ if isNotStream $service.Spec $service.Name $reqType }}Future<{{ $endpointName }}Response> {{untitle $endpointName}}({{ $endpointName }}Request req) async {
+		Request request = Request(
+			service: '{{$service.Name}}',
+			endpoint: '{{$endpointName}}',
+			body: req.toJson(),
+		);
+  
+		try {
+			Response res = await _client.call(request);
+			if (isError(res.body)) {
+			  final err = Merr(res.toJson());
+			  return {{ $endpointName }}Response.Merr(body: err.b);
+			}
+			return {{ $endpointName }}ResponseData.fromJson(res.body);
+		  } catch (e) {
+			throw Exception(e);
+		  }
+	}{{end}}
+	{{ if isStream $service.Spec $service.Name $reqType }}Stream<{{ $endpointName }}Response> {{untitle $endpointName}}({{ $endpointName }}Request req) async* {
+		Request request = Request(
+			service: '{{$service.Name}}',
+			endpoint: '{{$endpointName}}',
+			body: req.toJson(),
+		);
+		
+		try {
+			var webS = await _client.stream(request);
+			await for (var value in webS!) {
+				final vo = jsonDecode(value);
+				if (isError(vo)) {
+					yield {{ $endpointName }}Response.Merr(body: vo);
+				} else {
+					yield {{ $endpointName }}ResponseData.fromJson(vo);
+				}
+			}
+		} catch (e) {
+			throw Exception(e);
+		}
+	}{{end}}{{end}}
+}
+
+{{ range $typeName, $schema := $service.Spec.Components.Schemas }}
+{{ $isResponse := isResponse $typeName }}
+{{ if not $isResponse }}
+@Freezed()
+class {{ title $typeName }} with _${{ title $typeName }} {
+	{{ dartOneofAsserts $service $typeName }}const factory {{ title $typeName }}({{ recursiveTypeDefinitionDart $service $typeName $service.Spec.Components.Schemas }}) = _{{ title $typeName }};
+	factory {{ title $typeName }}.fromJson(Map<String, dynamic> json) =>
+      _${{ title $typeName }}FromJson(json);
+}
+{{ dartOneofs $service $typeName }}{{ end }}
+{{ if $isResponse }}
+@Freezed()
+class {{ title $typeName }} with _${{ title $typeName }} {
+	{{ dartOneofAsserts $service $typeName }}const factory {{ title $typeName }}({{ recursiveTypeDefinitionDart $service $typeName $service.Spec.Components.Schemas }}) = {{ title $typeName }}Data;
+	const factory {{ title $typeName }}.Merr({Map<String, dynamic>? body}) =
+	{{ title $typeName }}Merr;
+	factory {{ title $typeName }}.fromJson(Map<String, dynamic> json) =>
+      _${{ title $typeName }}FromJson(json);
+}
+{{ dartOneofs $service $typeName }}{{ end }}
+{{ end }}
+{{ dartEnums $service }}{{ dartWellKnownHelpers $service }}`
+
+// dartOneofTemplate renders the oneofs of a type, see dartOneofs
+const dartOneofTemplate = `{{ range $oneof := .oneofs }}
+{{ commentLines "/// " $oneof.comment }}/// the field of the oneof {{ $oneof.field }} of {{ $.type }} that is set
+@Freezed()
+class {{ $oneof.name }} with _${{ $oneof.name }} {
+{{ range $variant := $oneof.variants }}	const factory {{ $oneof.name }}.{{ $variant.constructor }}({{ $variant.type }} {{ $variant.constructor }}) = {{ $variant.class }};
+{{ end }}}
+
+extension {{ $oneof.name }}Field on {{ $.on }} {
+	/// the field of the oneof {{ $oneof.field }} that is set, null if none is
+	{{ $oneof.name }}? get {{ $oneof.getter }} {
+{{ range $variant := $oneof.variants }}		if ({{ $variant.field }} != null) {
+			return {{ $oneof.name }}.{{ $variant.constructor }}({{ $variant.field }}!);
+		}
+{{ end }}		return null;
+	}
+}
+{{ end }}`
+
+// dartEnumTemplate renders the enums used by a service, see dartEnums
+const dartEnumTemplate = `{{ range $enum := .enums }}
+{{ commentLines "/// " $enum.Comment }}enum {{ $enum.Name }} {
+{{ range $value := $enum.Values }}{{ commentLines "\t/// " $value.Comment }}	@JsonValue('{{ $value.Name }}')
+	{{ index $.names (printf "%v.%v" $enum.Name $value.Name) }},
+{{ end }}}
+{{ end }}`
+
+// dartWellKnownTemplate renders the JsonKey converters of the well-known
+// types used by a service, for single values, lists and maps
+const dartWellKnownTemplate = `{{ if .converters.timestamp }}
+// timestamps are encoded in UTC as RFC 3339 requires a time zone
+DateTime? _timestampFromJson(dynamic value) =>
+	value == null ? null : DateTime.parse(value as String);
+String? _timestampToJson(DateTime? value) => value?.toUtc().toIso8601String();
+{{ if .converters.timestampList }}List<DateTime>? _timestampListFromJson(dynamic value) =>
+	(value as List?)?.map((v) => _timestampFromJson(v)!).toList();
+List<String>? _timestampListToJson(List<DateTime>? value) =>
+	value?.map((v) => _timestampToJson(v)!).toList();
+{{ end }}{{ if .converters.timestampMap }}Map<String, DateTime>? _timestampMapFromJson(dynamic value) =>
+	(value as Map?)?.map((k, v) => MapEntry(k as String, _timestampFromJson(v)!));
+Map<String, String>? _timestampMapToJson(Map<String, DateTime>? value) =>
+	value?.map((k, v) => MapEntry(k, _timestampToJson(v)!));
+{{ end }}{{ end }}{{ if .converters.duration }}
+// durations are encoded as seconds with an "s" suffix, e.g. "1.5s"
+Duration? _durationFromJson(dynamic value) => value == null
+	? null
+	: Duration(microseconds: (double.parse((value as String).replaceAll('s', '')) * 1000000).round());
+String? _durationToJson(Duration? value) =>
+	value == null ? null : '${value.inMicroseconds / 1000000}s';
+{{ if .converters.durationList }}List<Duration>? _durationListFromJson(dynamic value) =>
+	(value as List?)?.map((v) => _durationFromJson(v)!).toList();
+List<String>? _durationListToJson(List<Duration>? value) =>
+	value?.map((v) => _durationToJson(v)!).toList();
+{{ end }}{{ if .converters.durationMap }}Map<String, Duration>? _durationMapFromJson(dynamic value) =>
+	(value as Map?)?.map((k, v) => MapEntry(k as String, _durationFromJson(v)!));
+Map<String, String>? _durationMapToJson(Map<String, Duration>? value) =>
+	value?.map((k, v) => MapEntry(k, _durationToJson(v)!));
+{{ end }}{{ end }}{{ if .converters.fieldMask }}
+// field masks are encoded as the comma separated paths
+List<String>? _fieldMaskFromJson(dynamic value) => value == null
+	? null
+	: (value as String).isEmpty
+		? <String>[]
+		: value.split(',');
+String? _fieldMaskToJson(List<String>? value) => value?.join(',');
+{{ if .converters.fieldMaskList }}List<List<String>>? _fieldMaskListFromJson(dynamic value) =>
+	(value as List?)?.map((v) => _fieldMaskFromJson(v)!).toList();
+List<String>? _fieldMaskListToJson(List<List<String>>? value) =>
+	value?.map((v) => _fieldMaskToJson(v)!).toList();
+{{ end }}{{ if .converters.fieldMaskMap }}Map<String, List<String>>? _fieldMaskMapFromJson(dynamic value) =>
+	(value as Map?)?.map((k, v) => MapEntry(k as String, _fieldMaskFromJson(v)!));
+Map<String, String>? _fieldMaskMapToJson(Map<String, List<String>>? value) =>
+	value?.map((k, v) => MapEntry(k, _fieldMaskToJson(v)!));
+{{ end }}{{ end }}{{ if .converters.int64 }}
+// 64 bit integers are encoded as strings, numbers are accepted too
+int _int64FromJson(dynamic value) =>
+	value is String ? int.parse(value) : (value as num).toInt();
+{{ end }}{{ if .converters.int64List }}
+List<int>? _int64ListFromJson(dynamic value) =>
+	(value as List?)?.map((v) => _int64FromJson(v)).toList();
+List<String>? _int64ListToJson(List<int>? value) =>
+	value?.map((v) => v.toString()).toList();
+{{ end }}{{ if .converters.int64Map }}
+Map<String, int>? _int64MapFromJson(dynamic value) =>
+	(value as Map?)?.map((k, v) => MapEntry(k as String, _int64FromJson(v)));
+Map<String, String>? _int64MapToJson(Map<String, int>? value) =>
+	value?.map((k, v) => MapEntry(k, v.toString()));
+{{ end }}{{ if .converters.int64IntMap }}
+// the integer keys of maps are encoded as strings too
+Map<int, int>? _int64IntMapFromJson(dynamic value) =>
+	(value as Map?)?.map((k, v) => MapEntry(int.parse(k as String), _int64FromJson(v)));
+Map<String, String>? _int64IntMapToJson(Map<int, int>? value) =>
+	value?.map((k, v) => MapEntry(k.toString(), v.toString()));
+{{ end }}{{ if .converters.int64BoolMap }}
+// the bool keys of maps are encoded as strings too
+Map<bool, int>? _int64BoolMapFromJson(dynamic value) =>
+	(value as Map?)?.map((k, v) => MapEntry(k == 'true', _int64FromJson(v)));
+Map<String, String>? _int64BoolMapToJson(Map<bool, int>? value) =>
+	value?.map((k, v) => MapEntry(k.toString(), v.toString()));
+{{ end }}`
+
+const dartExampleTemplate = `{{ $service := .service }}import 'dart:io';
+
+import '{{ .config.ImportPath }}/src/{{ $service.Name }}/{{ $service.Name }}.dart';
+
+void main() async {
+  final ser = {{title $service.Name}}Service(Platform.environment['{{ .config.TokenEnv }}']!);
+ 
+  final payload = <String, dynamic>{{ dartExampleRequest .example.Request }};
+
+  {{ title .endpoint }}Request req = {{ title .endpoint }}Request.fromJson(payload);
+
+  
+  try {
+	  {{ $reqType := requestType .endpoint }}
+	  {{ if isNotStream $service.Spec $service.Name $reqType }}
+	  {{ title .endpoint }}Response res = await ser.{{ .endpoint }}(req);
+
+    res.map((value) => print(value),
+        Merr: ({{ title .endpoint }}ResponseMerr err) => print(err.body!['body']));
+
+	  {{ end }}	
+	  {{ if isStream $service.Spec $service.Name $reqType }}
+	  final res = await ser.{{ .endpoint }}(req);
+		await for (var sr in res) {
+		sr.map((value) => print(value),
+			Merr: ({{ title .endpoint }}ResponseMerr err) => print(err.body));
+		}	
+	  {{ end }}
+  } catch (e) {
+    print(e);
+  } finally {
+    exit(0);
+  }
+}`
+
+const dartReadmeTopTemplate = `{{ $service := .service }}# {{ title $service.Name }}
+
+An [m3o.com](https://m3o.com) API. For example usage see [m3o.com/{{ $service.Name }}/api](https://m3o.com/{{ $service.Name }}/api).
+
+Endpoints:
+
+`
+
+const dartReadmeBottomTemplate = `{{ $service := .service }}## {{ title .endpoint }}
+
+{{ endpointDescription .endpoint $service.Spec.Components.Schemas }}
+
+[https://m3o.com/{{ $service.Name }}/api#{{ title .endpoint}}](https://m3o.com/{{ $service.Name }}/api#{{ title .endpoint}})
+
+` + "```" + `dart
+{{ $service := .service -}}import 'dart:io';
+
+import '{{ .config.ImportPath }}/src/{{ $service.Name }}/{{ $service.Name }}.dart';
+
+void main() async {
+  final ser = {{title $service.Name}}Service(Platform.environment['{{ .config.TokenEnv }}']!);
+ 
+  final payload = <String, dynamic>{{ dartExampleRequest .example.Request }};
+
+  {{ title .endpoint }}Request req = {{ title .endpoint }}Request.fromJson(payload);
+
+  {{ $reqType := requestType .endpoint }}
+  {{ if isNotStream $service.Spec $service.Name $reqType -}}
+  try {
+
+	{{ title .endpoint }}Response res = await ser.{{ .endpoint }}(req);
+
+    res.map((value) => print(value),
+	  Merr: ({{ title .endpoint }}ResponseMerr err) => print(err.body!['body']));
+  {{- end }}	
+  {{ if isStream $service.Spec $service.Name $reqType -}}
+  try {
+
+    final res = await ser.{{ .endpoint }}(req);
+
+	  await for (var sr in res) {
+	  sr.map((value) => print(value),
+		Merr: ({{ title .endpoint }}ResponseMerr err) => print(err.body));
+	  }	
+	{{- end }}
+  } catch (e) {
+    print(e);
+  } finally {
+    exit(0);
+  }
+}
+` + "```" + `
+`
@@ -0,0 +1,88 @@
+package gen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stoewer/go-strcase"
+)
+
+// enum is a proto enum used by the messages of a service
+type enum struct {
+	// flat name of the enum, see flatName
+	Name    string
+	Comment string
+	Values  []enumValue
+}
+
+// enumValue is a value of an enum, named as in the proto as that's how it's
+// encoded in JSON
+type enumValue struct {
+	Name    string
+	Comment string
+	// name without the prefix of the enum if it has one, in camel case,
+	// e.g. "Active" for STATUS_ACTIVE of enum Status
+	Short string
+}
+
+// serviceEnums returns the enums used by the fields and map values of the
+// messages of a service, sorted by name
+func serviceEnums(service Service) []enum {
+	enums := map[string]*desc.EnumDescriptor{}
+	for _, m := range serviceMessages(service) {
+		for _, field := range m.GetFields() {
+			if field.IsMap() {
+				field = field.GetMapValueType()
+			}
+			if e := field.GetEnumType(); e != nil {
+				enums[flatName(e)] = e
+			}
+		}
+	}
+
+	ret := []enum{}
+	for name, e := range enums {
+		en := enum{
+			Name:    name,
+			Comment: comment(e.GetSourceInfo()),
+		}
+		prefix := strcase.UpperSnakeCase(e.GetName()) + "_"
+		for _, v := range e.GetValues() {
+			short := v.GetName()
+			// values are often prefixed with the name of the enum as enum
+			// values share the scope of the enum in protobuf
+			if trimmed := strings.TrimPrefix(short, prefix); trimmed != short && trimmed != "" && !strings.ContainsAny(trimmed[:1], "0123456789") {
+				short = trimmed
+			}
+			en.Values = append(en.Values, enumValue{
+				Name:    v.GetName(),
+				Comment: comment(v.GetSourceInfo()),
+				Short:   strcase.UpperCamelCase(strings.ToLower(short)),
+			})
+		}
+		ret = append(ret, en)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// enumField returns the flat name of the enum of a field, empty if the
+// field isn't an enum or is a map, and whether the field is repeated
+func enumField(service Service, message, field string) (string, bool) {
+	files, err := parseProto(service)
+	if err != nil {
+		return "", false
+	}
+	msgDesc := files.message(message)
+	if msgDesc == nil {
+		return "", false
+	}
+	fieldDesc := msgDesc.FindFieldByName(field)
+	if fieldDesc == nil || fieldDesc.IsMap() || fieldDesc.GetEnumType() == nil {
+		return "", false
+	}
+	return flatName(fieldDesc.GetEnumType()), fieldDesc.IsRepeated()
+}
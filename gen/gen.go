@@ -0,0 +1,436 @@
+// Package gen generates the m3o clients and examples of a set of services
+// from their openapi specs and examples.json files.
+package gen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stoewer/go-strcase"
+)
+
+// Options configures a Generate run
+type Options struct {
+	// Target is the name of the target to generate, see Targets
+	Target string
+	// Generator, when set, is used instead of the generator of Target
+	Generator Generator
+	// Config of the generated code, see DefaultConfig
+	Config Config
+	// Output receives the generated files, DiskOutput when nil
+	Output Output
+
+	// Root is the folder holding a folder per service
+	Root string
+	// ClientPath is the folder the clients of the target are written to
+	ClientPath string
+	// ExamplesPath is the folder the examples are written to, in a folder
+	// per target
+	ExamplesPath string
+	// Services are the names of the service folders of Root, all the folders
+	// of Root when nil, see ServiceDirs
+	Services []string
+	// Match selects the services to generate, all of them when nil. The
+	// index file still lists every service.
+	Match func(serviceName string) bool
+
+	// Jobs is the number of services generated concurrently, at least 1
+	Jobs int
+	// NoMake skips building the specs with "make api"
+	NoMake bool
+	// Strict fails services whose "make api" fails instead of using the
+	// spec already on disk
+	Strict bool
+	// SpecDir is a folder of <service>.json and <service>.proto files to
+	// use instead of the ones in the service folders
+	SpecDir string
+	// FromProto derives the specs from the service protos
+	FromProto bool
+	// ProtoPaths are the folders the imports of the service protos are
+	// looked up in, after the folder of each proto
+	ProtoPaths []string
+	// LoadSpec, when set, loads the spec of a service instead, e.g. from a
+	// location other than the service folders
+	LoadSpec func(ctx context.Context, serviceName string) (*openapi3.Swagger, error)
+	// Cache, when set, skips the services whose inputs didn't change since
+	// they were last generated. It's only meant for runs writing to disk.
+	Cache *Cache
+	// Protos caches the parsed protos, a cache is created for the run when
+	// nil
+	Protos *ProtoCache
+	// Manifest, when set, is updated with the files generated for every
+	// service of Target, the services missing from Services are dropped.
+	// It's not updated when a custom Generator is used.
+	Manifest *Manifest
+}
+
+// Generate renders the clients and examples of the services selected by
+// opts.Match, processing up to opts.Jobs services concurrently. A failing
+// service doesn't stop the others, the returned MultiError holds a
+// ServiceError per failed service. The index file still covers every
+// service of the root folder, including the ones that failed.
+func Generate(ctx context.Context, opts Options) error {
+	// a generator is created per service to find out the files of each
+	// service for the manifest
+	newGenerator := func() (Generator, *recorder) {
+		return opts.Generator, nil
+	}
+	if opts.Generator == nil {
+		t, ok := FindTarget(opts.Target)
+		if !ok {
+			return fmt.Errorf("unknown target %q", opts.Target)
+		}
+		out := opts.Output
+		if out == nil {
+			out = DiskOutput{}
+		}
+		newGenerator = func() (Generator, *recorder) {
+			rec := newRecorder(out)
+			return t.NewGenerator(rec, opts.Config), rec
+		}
+	}
+	if opts.Protos == nil {
+		opts.Protos = NewProtoCache()
+	}
+	jobs := opts.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	log.Println("statring generator ...")
+	log.Printf("path: %v\n", opts.ClientPath)
+	log.Printf("workDir: %v\n", opts.Root)
+	log.Printf("examplePath: %v\n", opts.ExamplesPath)
+
+	names := opts.Services
+	if names == nil {
+		var err error
+		names, err = ServiceDirs(opts.Root, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// results are stored by index so the order of the services passed to
+	// IndexFile doesn't depend on which worker finishes first
+	services := make([]*Service, len(names))
+	errs := make([]error, len(names))
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if err := ctx.Err(); err != nil {
+					errs[i] = &ServiceError{Service: names[i], Err: err}
+					continue
+				}
+				g, rec := newGenerator()
+				services[i], errs[i] = generateService(ctx, g, names[i], opts)
+				// services not generated keep the files of their last run
+				if opts.Manifest != nil && rec != nil && errs[i] == nil && services[i] != nil && services[i].Spec != nil {
+					opts.Manifest.setFiles(opts.Target, names[i], rec.files())
+				}
+			}
+		}()
+	}
+	for i := range names {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	merr := MultiError{}
+	index := []Service{}
+	for i, s := range services {
+		if errs[i] != nil {
+			log.Printf("Failed to generate %v: %v\n", names[i], errs[i])
+			merr = append(merr, errs[i])
+			// the index only needs the name of the service
+			s = &Service{Name: names[i], ImportName: opts.Config.ImportName(names[i])}
+		}
+		if s != nil {
+			index = append(index, *s)
+		}
+	}
+	g, rec := newGenerator()
+	if err := g.IndexFile(opts.ClientPath, index); err != nil {
+		merr = append(merr, &ServiceError{Service: "(index)", Err: err})
+	} else if opts.Manifest != nil && rec != nil {
+		opts.Manifest.setFiles(opts.Target, "", rec.files())
+	}
+	if opts.Manifest != nil && rec != nil {
+		opts.Manifest.keepServices(opts.Target, names)
+	}
+	if len(merr) > 0 {
+		return merr
+	}
+	return nil
+}
+
+// generateService renders the client and examples of a single service, it
+// returns a nil service for services marked to be skipped. Services not
+// selected are only returned for the index file, which needs nothing but
+// their name. Errors are returned as a ServiceError.
+func generateService(ctx context.Context, g Generator, serviceName string, opts Options) (*Service, error) {
+	serviceDir := filepath.Join(opts.Root, serviceName)
+	examplesPath := opts.ExamplesPath
+	if opts.Match != nil && !opts.Match(serviceName) {
+		if Skipped(serviceDir) {
+			return nil, nil
+		}
+		s := newService(serviceName, nil, opts)
+		return &s, nil
+	}
+
+	if Skipped(serviceDir) {
+		return nil, nil
+	}
+
+	cacheKey := opts.Target + "/" + serviceName
+	if opts.Cache != nil {
+		hash, err := opts.Cache.inputsHash(serviceName, opts)
+		if err != nil {
+			return nil, &ServiceError{Service: serviceName, Err: err}
+		}
+		if hash != "" && hash == opts.Cache.get(cacheKey) {
+			log.Printf("Service %v is unchanged, skipping\n", serviceName)
+			s := newService(serviceName, nil, opts)
+			return &s, nil
+		}
+		// forget the service until it's generated again
+		opts.Cache.set(cacheKey, "")
+	}
+
+	spec, skip, err := ServiceSpec(ctx, serviceName, opts)
+	if err != nil {
+		return nil, &ServiceError{Service: serviceName, Err: err}
+	}
+	if skip {
+		return nil, nil
+	}
+
+	service := newService(serviceName, spec, opts)
+
+	if err := g.ServiceClient(serviceName, opts.ClientPath, service); err != nil {
+		return nil, &ServiceError{Service: serviceName, Err: err}
+	}
+	if err := g.TopReadme(serviceName, examplesPath, service); err != nil {
+		return nil, &ServiceError{Service: serviceName, Err: err}
+	}
+
+	m, err := LoadExamples(serviceDir)
+	if err != nil {
+		return nil, &ServiceError{Service: serviceName, Err: err}
+	}
+	// endpoints are sorted so the READMEs list them in the same order
+	endpoints := []string{}
+	for endpoint := range m {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		for _, example := range m[endpoint] {
+			title := regexp.MustCompile("[^a-zA-Z0-9]+").ReplaceAllString(strcase.LowerCamelCase(strings.Replace(example.Title, " ", "_", -1)), "")
+
+			err := g.ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title, service, example)
+			if err != nil {
+				return nil, &ServiceError{Service: serviceName, Endpoint: endpoint, Example: example.Title, Err: err}
+			}
+		}
+	}
+
+	if opts.Cache != nil {
+		// hashed again as "make api" may have updated the spec
+		hash, err := opts.Cache.inputsHash(serviceName, opts)
+		if err != nil {
+			return nil, &ServiceError{Service: serviceName, Err: err}
+		}
+		opts.Cache.set(cacheKey, hash)
+	}
+	return &service, nil
+}
+
+// ServiceSpec loads the openapi spec of a service and reports whether the
+// service is marked to be skipped. Unless disabled, the spec is built with
+// "make api" first, in strict mode a failing build is an error instead of
+// falling back to the spec already on disk. Specs read from a spec folder
+// are used as they are and specs derived from the protos never need a
+// build.
+func ServiceSpec(ctx context.Context, serviceName string, opts Options) (*openapi3.Swagger, bool, error) {
+	serviceDir := filepath.Join(opts.Root, serviceName)
+	if Skipped(serviceDir) {
+		return nil, true, nil
+	}
+	if opts.LoadSpec != nil {
+		spec, err := opts.LoadSpec(ctx, serviceName)
+		return spec, false, err
+	}
+	if opts.FromProto {
+		spec, err := protoSpec(serviceName, ProtoFile(serviceName, opts), opts.ProtoPaths)
+		return spec, false, err
+	}
+	if opts.SpecDir != "" {
+		spec, err := readSpec(filepath.Join(opts.SpecDir, serviceName+".json"))
+		return spec, false, err
+	}
+
+	if !opts.NoMake {
+		cmd := exec.CommandContext(ctx, "make", "api")
+		cmd.Dir = serviceDir
+		outp, err := cmd.CombinedOutput()
+		if err != nil && opts.Strict {
+			return nil, false, fmt.Errorf("make api failed: %v\n%v", err, string(outp))
+		}
+		if err != nil {
+			log.Println(string(outp))
+		}
+	}
+
+	serviceFiles, err := ioutil.ReadDir(serviceDir)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read service dir: %v", err)
+	}
+	return apiSpec(serviceFiles, serviceDir)
+}
+
+// ProtoFile returns the path of the proto file of a service
+func ProtoFile(serviceName string, opts Options) string {
+	if opts.SpecDir != "" {
+		return filepath.Join(opts.SpecDir, serviceName+".proto")
+	}
+	return filepath.Join(opts.Root, serviceName, "proto", serviceName+".proto")
+}
+
+func newService(name string, spec *openapi3.Swagger, opts Options) Service {
+	return Service{
+		Name:       name,
+		ImportName: opts.Config.ImportName(name),
+		Spec:       spec,
+		Proto:      ProtoFile(name, opts),
+		ProtoPaths: opts.ProtoPaths,
+		protos:     opts.Protos,
+		config:     opts.Config,
+	}
+}
+
+// ServiceDirs returns the sorted names of the service folders found in root,
+// hidden folders, the generated clients and examples trees and the folders
+// holding any of the exclude paths are ignored
+func ServiceDirs(root string, exclude []string) ([]string, error) {
+	files, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if strings.Contains(f.Name(), "clients") || strings.Contains(f.Name(), "examples") {
+			continue
+		}
+		if !f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, f.Name())
+		excluded := false
+		for _, path := range exclude {
+			if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+				excluded = true
+			}
+		}
+		if !excluded {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Skipped reports whether a service folder is marked to be ignored
+func Skipped(serviceDir string) bool {
+	_, err := os.Stat(filepath.Join(serviceDir, "skip"))
+	return err == nil
+}
+
+// ExamplesFile returns the path of the examples.json of a service, it's
+// either at the root of the service folder or in its config folder. The
+// path at the root is returned when there's neither.
+func ExamplesFile(serviceDir string) string {
+	path := filepath.Join(serviceDir, "examples.json")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	alt := filepath.Join(serviceDir, "config", "examples.json")
+	if _, err := os.Stat(alt); err == nil {
+		return alt
+	}
+	return path
+}
+
+// LoadExamples reads the examples.json of a service, see ExamplesFile
+func LoadExamples(serviceDir string) (map[string][]Example, error) {
+	path := ExamplesFile(serviceDir)
+	exam, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string][]Example{}
+	err = json.Unmarshal(exam, &m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %v: %v", path, err)
+	}
+	return m, nil
+}
+
+// MultiError collects the errors of services processed concurrently
+type MultiError []error
+
+func (m MultiError) Error() string {
+	msgs := []string{}
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ServiceError is the failure of a single service, with the endpoint and
+// example being generated when it happened if any
+type ServiceError struct {
+	Target   string
+	Service  string
+	Endpoint string
+	Example  string
+	Err      error
+}
+
+func (e *ServiceError) Error() string {
+	msg := e.Service
+	if e.Target != "" {
+		msg = e.Target + " " + msg
+	}
+	if e.Endpoint != "" {
+		msg += " " + e.Endpoint
+	}
+	if e.Example != "" {
+		msg += fmt.Sprintf(" example %q", e.Example)
+	}
+	return fmt.Sprintf("%v: %v", msg, e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
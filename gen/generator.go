@@ -0,0 +1,370 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/camelcase"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stoewer/go-strcase"
+)
+
+const (
+	FILE_EXECUTE_PERMISSION   = 0664
+	FOLDER_EXECUTE_PERMISSION = 0775
+)
+
+// Service is a service clients and examples are generated for
+type Service struct {
+	Spec *openapi3.Swagger
+	Name string
+	//  overwrite import name of service when it's a keyword ie function in javascript
+	ImportName string
+	// path of the proto file of the service, used for the types the spec
+	// doesn't describe such as the items of arrays and maps
+	Proto string
+	// folders the imports of Proto are looked up in after its own folder
+	ProtoPaths []string
+
+	// parsed protos of the run, see parseProto
+	protos *ProtoCache
+	// config of the target the service is generated for
+	config Config
+}
+
+// Example is an example request of an endpoint, as found in examples.json
+type Example struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Request      map[string]interface{}
+	Response     map[string]interface{}
+	RunCheck     bool   `json:"run_check"`
+	Idempotent   bool   `json:"idempotent"`
+	ShellRequest string `json:"shell_request"`
+}
+
+// Generator renders the clients and examples of a target. ServiceClient,
+// TopReadme and ExampleAndReadmeEdit are called concurrently for different
+// services, so implementations must be safe for concurrent use; the calls
+// for a single service are made in order from one goroutine. IndexFile is
+// called once all the services are done.
+type Generator interface {
+	ServiceClient(serviceName, path string, service Service) error
+	TopReadme(serviceName, examplesPath string, service Service) error
+	ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service Service, example Example) error
+	IndexFile(path string, services []Service) error
+}
+
+// render executes a template with the funcMap helpers, the errors returned
+// by the helpers end up in the error along with the template line
+func render(name, text string, data map[string]interface{}) ([]byte, error) {
+	templ, err := template.New(name).Funcs(funcMap()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	b := bytes.Buffer{}
+	err = templ.Execute(&b, data)
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func funcMap() map[string]interface{} {
+	isStream := func(spec *openapi3.Swagger, serviceName, requestType string) (bool, error) {
+		// eg. "/notes/Notes/Events":
+		path := fmt.Sprintf("/%v/%v/%v", serviceName, strings.Title(serviceName), strings.Replace(requestType, "Request", "", -1))
+		var p *openapi3.PathItem
+		for k, v := range spec.Paths {
+			if strings.ToLower(k) == strings.ToLower(path) {
+				p = v
+			}
+		}
+		if p == nil || p.Post == nil {
+			return false, fmt.Errorf("path %v not found in the spec", path)
+		}
+		if _, ok := p.Post.Responses["stream"]; ok {
+			return true, nil
+		}
+		return false, nil
+	}
+	endpointRequest := func(endpoint string, schemas map[string]*openapi3.SchemaRef) (*openapi3.SchemaRef, error) {
+		v := schemas[strings.Title(endpoint)+"Request"]
+		if v == nil {
+			return nil, fmt.Errorf("can't find %vRequest in the spec", strings.Title(endpoint))
+		}
+		return v, nil
+	}
+	return map[string]interface{}{
+		"isCustomShell": func(ex Example) bool {
+			return len(ex.ShellRequest) > 0
+		},
+		"recursiveTypeDefinitionGo": func(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+			gog := &goG{}
+			return gog.schemaToType(service, typeName, schemas)
+		},
+		// comment lines of text, empty when text is
+		"commentLines": func(prefix, text string) string {
+			if strings.TrimSpace(text) == "" {
+				return ""
+			}
+			ret := ""
+			for _, line := range strings.Split(text, "\n") {
+				ret += prefix + strings.TrimSpace(line) + "\n"
+			}
+			return ret
+		},
+		"goEnums":            goEnums,
+		"tsEnums":            tsEnums,
+		"dartEnums":          dartEnums,
+		"dartOneofAsserts":   dartOneofAsserts,
+		"dartOneofs":         dartOneofs,
+		"goImports":          goImports,
+		"goExampleImports":   goExampleImports,
+		"goExampleHelpers":   goExampleHelpers,
+		"goOneofs":           goOneofs,
+		"goWellKnownHelpers": goWellKnownHelpers,
+		"goInt64Helpers":     goInt64Helpers,
+		"tsConversions":      tsConversions,
+		"tsOneofs":           tsOneofs,
+		"recursiveTypeDefinitionTs": func(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+			tsg := &tsG{cfg: service.config}
+			return tsg.schemaToType(service, typeName, schemas)
+		},
+		"dartWellKnownHelpers": dartWellKnownHelpers,
+		"recursiveTypeDefinitionDart": func(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+			dartg := &dartG{}
+			return dartg.schemaToType(service, typeName, schemas)
+		},
+		"requestTypeToEndpointName": func(requestType string) string {
+			parts := camelcase.Split(requestType)
+			return strings.Join(parts[1:len(parts)-1], "")
+		},
+		// strips service name from the request type
+		"requestType": func(requestType string) string {
+			// @todo hack to support examples
+			if strings.ToLower(requestType[0:1]) == requestType[0:1] {
+				return strings.ToTitle(requestType[0:1]) + requestType[1:] + "Request"
+			}
+			parts := camelcase.Split(requestType)
+			return strings.Join(parts[1:], "")
+		},
+		"isStream": isStream,
+		"isNotStream": func(spec *openapi3.Swagger, serviceName, requestType string) (bool, error) {
+			stream, err := isStream(spec, serviceName, requestType)
+			return !stream, err
+		},
+		"isResponse": func(typeName string) bool {
+			// return true if typeName has 'Response' as suffix.
+			// this is primarily used in dart template.
+			return strings.HasSuffix(typeName, "Response")
+		},
+		// Similar to isStream, this function checks if a service has
+		// a stream endpoint or not
+		"serviceHasStream": func(spec *openapi3.Swagger, service string) bool {
+			for _, v := range spec.Paths {
+				if _, ok := v.Post.Responses["stream"]; ok {
+					return true
+				}
+			}
+			return false
+		},
+		"requestTypeToResponseType": func(requestType string) string {
+			parts := camelcase.Split(requestType)
+			return strings.Join(parts[1:len(parts)-1], "") + "Response"
+		},
+		"endpointComment": func(endpoint string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+			v, err := endpointRequest(endpoint, schemas)
+			if err != nil || v.Value == nil {
+				return "", err
+			}
+			comm := v.Value.Description
+			ret := ""
+			for _, line := range strings.Split(comm, "\n") {
+				ret += "// " + strings.TrimSpace(line) + "\n"
+			}
+			return ret, nil
+		},
+		// @todo same function as above
+		"endpointDescription": func(endpoint string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+			v, err := endpointRequest(endpoint, schemas)
+			if err != nil || v.Value == nil {
+				return "", err
+			}
+			comm := v.Value.Description
+			ret := ""
+			for _, line := range strings.Split(comm, "\n") {
+				ret += strings.TrimSpace(line) + "\n"
+			}
+			return ret, nil
+		},
+		"requestTypeToEndpointPath": func(requestType string) string {
+			parts := camelcase.Split(requestType)
+			return strings.Title(strings.Join(parts[1:len(parts)-1], ""))
+		},
+		"title": strings.Title,
+		"untitle": func(t string) string {
+			return strcase.LowerCamelCase(t)
+		},
+		"goExampleRequest": func(service Service, endpoint string, schemas map[string]*openapi3.SchemaRef, exampleJSON map[string]interface{}) (string, error) {
+			return schemaToGoExample(service, strings.Title(endpoint)+"Request", schemas, exampleJSON)
+		},
+		"tsExampleRequest": func(serviceName, endpoint string, schemas map[string]*openapi3.SchemaRef, exampleJSON map[string]interface{}) string {
+			bs, _ := json.MarshalIndent(exampleJSON, "", "  ")
+			return string(bs)
+		},
+		"dartExampleRequest": func(exampleJSON map[string]interface{}) string {
+			return schemaToDartExample(exampleJSON)
+		},
+		"cliExampleRequest": func(exampleJSON map[string]interface{}) string {
+			return schemaToCLIExample(exampleJSON)
+		},
+	}
+}
+
+func apiSpec(serviceFiles []os.FileInfo, serviceDir string) (*openapi3.Swagger, bool, error) {
+	// detect openapi json file
+	apiJSON := ""
+	skip := false
+	for _, serviceFile := range serviceFiles {
+		if strings.Contains(serviceFile.Name(), "api") && strings.Contains(serviceFile.Name(), "-") && strings.HasSuffix(serviceFile.Name(), ".json") {
+			apiJSON = filepath.Join(serviceDir, serviceFile.Name())
+		}
+		if serviceFile.Name() == "skip" {
+			skip = true
+		}
+	}
+	if skip {
+		return nil, true, nil
+	}
+
+	log.Println("Processing folder - apiSpec", serviceDir, "api json", apiJSON)
+
+	if apiJSON == "" {
+		return nil, false, fmt.Errorf("no api json spec found in %v", serviceDir)
+	}
+	spec, err := readSpec(apiJSON)
+	return spec, false, err
+}
+
+func readSpec(apiJSON string) (*openapi3.Swagger, error) {
+	js, err := ioutil.ReadFile(apiJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read json spec: %v", err)
+	}
+	spec := &openapi3.Swagger{}
+	err = json.Unmarshal(js, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %v: %v", apiJSON, err)
+	}
+	return spec, nil
+}
+
+func incBeta(ver semver.Version) (semver.Version, error) {
+	s := ver.String()
+	parts := strings.Split(s, "beta")
+	if len(parts) < 2 {
+		return semver.Version{}, fmt.Errorf("not a beta version %v", s)
+	}
+	i, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return semver.Version{}, err
+	}
+	i++
+	v, err := semver.NewVersion(parts[0] + "beta" + fmt.Sprintf("%v", i))
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return *v, nil
+}
+
+// propertyNames returns the property names of a message schema in the order
+// of the proto field numbers, so the generated code follows the proto. The
+// properties the proto doesn't describe come last, sorted by name.
+func propertyNames(service Service, message string, properties map[string]*openapi3.SchemaRef) []string {
+	names := []string{}
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	numbers := map[string]int32{}
+	if files, err := parseProto(service); err == nil {
+		if msgDesc := files.message(message); msgDesc != nil {
+			for _, field := range msgDesc.GetFields() {
+				numbers[field.GetName()] = field.GetNumber()
+			}
+		}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		a, aok := numbers[names[i]]
+		b, bok := numbers[names[j]]
+		if aok != bok {
+			return aok
+		}
+		return a < b
+	})
+	return names
+}
+
+// detectType detects the type of elements in an array, types of key/value elements in a map
+// also the type of enum directly from proto file for the specified
+// service, message and field name
+func detectType2(service Service, message, field string) ([]string, error) {
+	filePath := service.Proto
+
+	files, err := parseProto(service)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if the message exist, in the service proto or its imports
+	msgDesc := files.message(message)
+	if msgDesc == nil {
+		return nil, fmt.Errorf("could not find message %v in %v or its imports", message, filePath)
+	}
+
+	// check if the field exist
+	fieldDesc := msgDesc.FindFieldByName(field)
+	if fieldDesc == nil {
+		return nil, fmt.Errorf("could not find field %v in message %v of %v", field, message, filePath)
+	}
+
+	// Enum, Message and primitive types, messages and enums are named by
+	// their flat name so nested types don't clash, see flatName
+	typeOf := func(fieldDesc *desc.FieldDescriptor) string {
+		switch t := fieldDesc.GetType(); t.String() {
+		case "TYPE_ENUM":
+			return flatName(fieldDesc.GetEnumType())
+		case "TYPE_MESSAGE":
+			// check if the type is a well-known type
+			protoDesc := fieldDesc.AsFieldDescriptorProto()
+			s, ok := wellKnownTypes[*protoDesc.TypeName]
+			if ok {
+				return s
+			}
+			return flatName(fieldDesc.GetMessageType())
+		default:
+			// In case the type is primitive type
+			return strings.Split(t.String(), "_")[1]
+		}
+	}
+
+	// check if the field is a map
+	if fieldDesc.IsMap() {
+		fields := fieldDesc.GetMessageType().GetFields()
+		return []string{typeOf(fields[0]), typeOf(fields[1])}, nil
+	}
+	return []string{typeOf(fieldDesc)}, nil
+}
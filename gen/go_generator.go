@@ -0,0 +1,1069 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stoewer/go-strcase"
+)
+
+type goG struct {
+	Generator
+	out Output
+	cfg Config
+}
+
+func (g *goG) ServiceClient(serviceName, goPath string, service Service) error {
+	clientFile := filepath.Join(goPath, serviceName, fmt.Sprint(serviceName, ".go"))
+	b, err := render("go"+serviceName, goServiceTemplate, map[string]interface{}{
+		"service": service,
+		"config":  g.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", clientFile, err)
+	}
+	err = g.out.WriteFile(clientFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (g *goG) TopReadme(serviceName, examplesPath string, service Service) error {
+	readme := filepath.Join(examplesPath, "go", serviceName, "README.md")
+	b, err := render("goTopReadme"+serviceName, goReadmeTopTemplate, map[string]interface{}{
+		"service": service,
+		"config":  g.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", readme, err)
+	}
+	err = g.out.WriteFile(readme, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (g *goG) ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service Service, example Example) error {
+	data := map[string]interface{}{
+		"service":  service,
+		"config":   g.cfg,
+		"example":  example,
+		"endpoint": endpoint,
+		"funcName": strcase.UpperCamelCase(title),
+	}
+
+	exampleFile := filepath.Join(examplesPath, "go", serviceName, endpoint, title, "main.go")
+	b, err := render("go"+serviceName+endpoint, goExampleTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", exampleFile, err)
+	}
+	err = g.out.WriteFile(exampleFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	if example.RunCheck && example.Idempotent {
+		err = g.out.WriteFile(filepath.Join(examplesPath, "go", serviceName, endpoint, title, ".run"), []byte{})
+		if err != nil {
+			return fmt.Errorf("failed to write run file: %v", err)
+		}
+	}
+
+	// per endpoint go readme examples
+	readmeAppend := filepath.Join(examplesPath, "go", serviceName, "README.md")
+	b, err = render("goReadmebottom"+serviceName+endpoint, goReadmeBottomTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", readmeAppend, err)
+	}
+	err = g.out.AppendFile(readmeAppend, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (g *goG) IndexFile(goPath string, services []Service) error {
+	indexFile := filepath.Join(goPath, "m3o.go")
+	b, err := render("goclient", goIndexTemplate, map[string]interface{}{
+		"services": services,
+		"config":   g.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", indexFile, err)
+	}
+	err = g.out.WriteFile(indexFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (g *goG) schemaToType(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+	var normalType = `{{ .parameter }} {{ .type }}`
+	var arrayType = `{{ .parameter }} []{{ .type }}`
+	var mapType = ` {{ .parameter }} map[{{ .type1 }}]{{ .type2 }}`
+	var anyType = `{{ .parameter }} interface{}`
+	var stringType = "string"
+	var int32Type = "int32"
+	var int64Type = "int64"
+	var floatType = "float32"
+	var doubleType = "float64"
+	var boolType = "bool"
+	var pointerType = "*"
+
+	runTemplate := func(tmpName, temp string, payload map[string]interface{}) string {
+		t, err := template.New(tmpName).Parse(temp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to parse %s - err: %v\n", temp, err)
+			return ""
+		}
+		var tb bytes.Buffer
+		err = t.Execute(&tb, payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "faild to apply parsed template %s to payload %v - err: %v\n", temp, payload, err)
+			return ""
+		}
+
+		return tb.String()
+	}
+
+	typesMapper := func(t string) string {
+		switch t {
+		case "STRING":
+			return stringType
+		case "INT32":
+			return int32Type
+		case "INT64":
+			return int64Type
+		case "SINT32", "SFIXED32":
+			return int32Type
+		case "SINT64", "SFIXED64":
+			return int64Type
+		case "UINT32", "FIXED32":
+			return "uint32"
+		case "UINT64", "FIXED64":
+			return "uint64"
+		case "FLOAT":
+			return floatType
+		case "DOUBLE":
+			return doubleType
+		case "BOOL":
+			return boolType
+		default:
+			if wkt, ok := goWellKnownTypes[t]; ok {
+				return wkt
+			}
+			return t
+		}
+	}
+
+	output := []string{}
+	protoMessage := schemas[typeName]
+	if protoMessage == nil || protoMessage.Value == nil {
+		return "", fmt.Errorf("can't find %v in the spec", typeName)
+	}
+
+	// return an empty string if there is no properties for the typeName
+	if len(protoMessage.Value.Properties) == 0 {
+		return "", nil
+	}
+
+	for _, p := range propertyNames(service, typeName, protoMessage.Value.Properties) {
+		meta := protoMessage.Value.Properties[p]
+		comments := ""
+		o := ""
+
+		if meta.Value.Description != "" {
+			for _, commentLine := range strings.Split(meta.Value.Description, "\n") {
+				comments += "// " + strings.TrimSpace(commentLine) + "\n"
+			}
+		}
+		if oo := oneofOf(service, typeName, p); oo != nil {
+			comments += fmt.Sprintf("// only one of %v may be set, see Validate\n", oo.names(strcase.UpperCamelCase))
+		}
+
+		// lists and maps of 64 bit integers encode them as strings, like the
+		// string option does for single ones
+		if kind, key := int64Values(service, typeName, p); kind != "" {
+			o = strcase.UpperCamelCase(p) + " " + goInt64Type(kind, key) + fmt.Sprintf(" `json:\"%v,omitempty\"`", p)
+			output = append(output, comments+o)
+			continue
+		}
+
+		// well-known types are mapped to native types, whatever the spec says
+		if wkt, repeated, isMap := wellKnownType(service, typeName, p); wkt != "" && !isMap {
+			o = strcase.UpperCamelCase(p) + " " + goWellKnownType(wkt, repeated)
+			// 64 bit wrappers are encoded as strings like int64
+			if !repeated && (wkt == "INT64VALUE" || wkt == "UINT64VALUE") {
+				o += fmt.Sprintf(" `json:\"%v,string,omitempty\"`", p)
+			} else {
+				o += fmt.Sprintf(" `json:\"%v,omitempty\"`", p)
+			}
+			output = append(output, comments+o)
+			continue
+		}
+		// proto3 optional fields are pointers so their zero values are sent
+		if t := goOptionalType(service, typeName, p, meta.Value); t != "" {
+			o = strcase.UpperCamelCase(p) + " *" + t
+			if meta.Value.Format == "int64" || meta.Value.Format == "uint64" {
+				o += fmt.Sprintf(" `json:\"%v,string,omitempty\"`", p)
+			} else {
+				o += fmt.Sprintf(" `json:\"%v,omitempty\"`", p)
+			}
+			output = append(output, comments+o)
+			continue
+		}
+		if enum, repeated := enumField(service, typeName, p); enum != "" && !repeated {
+			o = strcase.UpperCamelCase(p) + " " + enum + fmt.Sprintf(" `json:\"%v,omitempty\"`", p)
+			output = append(output, comments+o)
+			continue
+		}
+
+		switch meta.Value.Type {
+		case "string":
+			payload := map[string]interface{}{
+				"type":      stringType,
+				"parameter": strcase.UpperCamelCase(p),
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "boolean":
+			payload := map[string]interface{}{
+				"type":      boolType,
+				"parameter": strcase.UpperCamelCase(p),
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "number":
+			switch meta.Value.Format {
+			case "int32":
+				payload := map[string]interface{}{
+					"type":      int32Type,
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("normal", normalType, payload)
+			case "int64":
+				payload := map[string]interface{}{
+					"type":      int64Type,
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("normal", normalType, payload)
+			case "uint32", "uint64":
+				payload := map[string]interface{}{
+					"type":      meta.Value.Format,
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("normal", normalType, payload)
+			case "float":
+				payload := map[string]interface{}{
+					"type":      floatType,
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("normal", normalType, payload)
+			case "double":
+				payload := map[string]interface{}{
+					"type":      doubleType,
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("normal", normalType, payload)
+			}
+		case "array":
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			payload := map[string]interface{}{
+				"type":      typesMapper(types[0]),
+				"parameter": strcase.UpperCamelCase(p),
+			}
+			o = runTemplate("array", arrayType, payload)
+		case "object":
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			// a Message Type
+			if len(types) == 1 {
+				t := pointerType + typesMapper(types[0])
+				// protobuf external type
+				if types[0] == "JSON" {
+					t = typesMapper(types[0])
+				}
+				payload := map[string]interface{}{
+					"type":      t,
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("normal", normalType, payload)
+			} else {
+				// a Map object
+				payload := map[string]interface{}{
+					"type1":     typesMapper(types[0]),
+					"type2":     typesMapper(types[1]),
+					"parameter": strcase.UpperCamelCase(p),
+				}
+				o = runTemplate("map", mapType, payload)
+			}
+		default:
+			payload := map[string]interface{}{
+				"parameter": strcase.UpperCamelCase(p),
+			}
+			o = runTemplate("any", anyType, payload)
+		}
+
+		// int64 represented as string
+		if meta.Value.Format == "int64" || meta.Value.Format == "uint64" {
+			o += fmt.Sprintf(" `json:\"%v,string,omitempty\"`", p)
+		} else {
+			o += fmt.Sprintf(" `json:\"%v,omitempty\"`", p)
+		}
+
+		output = append(output, comments+o)
+	}
+
+	return strings.Join(output, "\n"), nil
+}
+
+func schemaToGoExample(service Service, endpoint string, schemas map[string]*openapi3.SchemaRef, exa map[string]interface{}) (string, error) {
+
+	var requestAttr = `{{ .parameter }}: {{ .value }}`
+	var primitiveArrRequestAttr = `{{ .parameter }}: []{{ .type }}`
+	var arrRequestAttr = `{{ .parameter }}: []{{ .service }}.{{ .message }}`
+	var objRequestAttr = `{{ .parameter }}: &{{ .service }}.{{ .message }}`
+	var jsonType = "map[string]interface{}"
+	var stringType = "string"
+	var int32Type = "int32"
+	var int64Type = "int64"
+	var floatType = "float32"
+	var doubleType = "float64"
+	var boolType = "bool"
+
+	runTemplate := func(tmpName, temp string, payload map[string]interface{}) string {
+		t, err := template.New(tmpName).Parse(temp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to parse %s - err: %v\n", temp, err)
+			return ""
+		}
+		var tb bytes.Buffer
+		err = t.Execute(&tb, payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "faild to apply parsed template %s to payload %v - err: %v\n", temp, payload, err)
+			return ""
+		}
+
+		return tb.String()
+	}
+
+	typesMapper := func(t string) string {
+		switch t {
+		case "STRING":
+			return stringType
+		case "INT32":
+			return int32Type
+		case "INT64":
+			return int64Type
+		case "SINT32", "SFIXED32":
+			return int32Type
+		case "SINT64", "SFIXED64":
+			return int64Type
+		case "UINT32", "FIXED32":
+			return "uint32"
+		case "UINT64", "FIXED64":
+			return "uint64"
+		case "FLOAT":
+			return floatType
+		case "DOUBLE":
+			return doubleType
+		case "BOOL":
+			return boolType
+		case "JSON":
+			return jsonType
+		default:
+			return t
+		}
+	}
+
+	var traverse func(p string, message string, metaData *openapi3.SchemaRef, attrValue interface{}) (string, error)
+	traverse = func(p, message string, metaData *openapi3.SchemaRef, attrValue interface{}) (string, error) {
+		o := ""
+
+		if wkt, repeated, isMap := wellKnownType(service, message, p); wkt != "" && !isMap {
+			value, err := goWellKnownExample(service.Name, wkt, repeated, attrValue)
+			if err != nil {
+				return "", fmt.Errorf("%v: %v", p, err)
+			}
+			return strcase.UpperCamelCase(p) + ": " + value, nil
+		}
+		if t := goOptionalType(service, message, p, metaData.Value); t != "" {
+			value := fmt.Sprint(attrValue)
+			if t == "string" {
+				value = fmt.Sprintf("%q", attrValue)
+			}
+			return fmt.Sprintf("%v: %v.%v(%v)", strcase.UpperCamelCase(p), service.Name, strings.Title(t), value), nil
+		}
+
+		switch metaData.Value.Type {
+		case "string":
+			value := fmt.Sprintf("%q", attrValue)
+			if enum, repeated := enumField(service, message, p); enum != "" && !repeated {
+				value = goEnumConstant(service, enum, attrValue)
+			}
+			payload := map[string]interface{}{
+				"parameter": strcase.UpperCamelCase(p),
+				"value":     value,
+			}
+			o = runTemplate("requestAttr", requestAttr, payload)
+		case "boolean":
+			value, ok := attrValue.(bool)
+			if !ok {
+				return "", fmt.Errorf("%v should be a boolean, got %v", p, attrValue)
+			}
+			payload := map[string]interface{}{
+				"parameter": strcase.UpperCamelCase(p),
+				"value":     value,
+			}
+			o = runTemplate("requestAttr", requestAttr, payload)
+		case "number":
+			switch metaData.Value.Format {
+			case "int32", "int64", "uint32", "uint64", "float", "double":
+				payload := map[string]interface{}{
+					"parameter": strcase.UpperCamelCase(p),
+					"value":     attrValue,
+				}
+				o = runTemplate("requestAttr", requestAttr, payload)
+			}
+		case "array":
+			if kind, key := int64Values(service, message, p); kind != "" && key == "" {
+				items, ok := attrValue.([]interface{})
+				if !ok {
+					return "", fmt.Errorf("%v should be an array, got %v", p, attrValue)
+				}
+				values := []string{}
+				for _, item := range items {
+					v, err := goInt64Literal(item)
+					if err != nil {
+						return "", fmt.Errorf("%v: %v", p, err)
+					}
+					values = append(values, v)
+				}
+				return fmt.Sprintf("%v: []%v{%v}", strcase.UpperCamelCase(p), strings.ToLower(kind), strings.Join(values, ", ")), nil
+			}
+
+			// TODO(daniel): with this approach, we lost the second item (if exists)
+			// see the contact/Create example, the phone has two items and with this
+			// approach we only populate one.
+
+			items, ok := attrValue.([]interface{})
+			if !ok {
+				return "", fmt.Errorf("%v should be an array, got %v", p, attrValue)
+			}
+			messageType, err := detectType2(service, message, p)
+			if err != nil {
+				return "", err
+			}
+			for _, item := range items {
+				switch item := item.(type) {
+				case map[string]interface{}:
+					payload := map[string]interface{}{
+						"service":   service.Name,
+						"message":   strcase.UpperCamelCase(messageType[0]),
+						"parameter": strcase.UpperCamelCase(p),
+					}
+					o = runTemplate("arrRequestAttr", arrRequestAttr, payload) + "{\n"
+					o += service.Name + "." + messageType[0] + ": {\n"
+					properties := metaData.Value.Items.Value.Properties
+					for _, p := range propertyNames(service, messageType[0], properties) {
+						v, ok := item[p]
+						if !ok {
+							continue
+						}
+						attr, err := traverse(p, messageType[0], properties[p], v)
+						if err != nil {
+							return "", err
+						}
+						o += attr + ", "
+					}
+					o += "},\n"
+				default:
+					t := typesMapper(messageType[0])
+					// enums are declared in the service package
+					if enum, _ := enumField(service, message, p); enum != "" {
+						t = service.Name + "." + enum
+					}
+					payload := map[string]interface{}{
+						"type":      t,
+						"parameter": strcase.UpperCamelCase(p),
+					}
+					o = runTemplate("primitiveArrRequestAttr", primitiveArrRequestAttr, payload) + "{\n"
+					o += fmt.Sprintf("%q,\n", item)
+				}
+			}
+			o += "}"
+		case "object":
+			value, ok := attrValue.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("%v should be an object, got %v", p, attrValue)
+			}
+			if kind, key := int64Values(service, message, p); kind != "" && key != "" {
+				entries := []string{}
+				for _, k := range sortedKeys(value) {
+					v, err := goInt64Literal(value[k])
+					if err != nil {
+						return "", fmt.Errorf("%v: %v", p, err)
+					}
+					if key == "STRING" {
+						k = fmt.Sprintf("%q", k)
+					}
+					entries = append(entries, k+": "+v)
+				}
+				return fmt.Sprintf("%v: map[%v]%v{%v}", strcase.UpperCamelCase(p), goInt64Keys[key], strings.ToLower(kind), strings.Join(entries, ", ")), nil
+			}
+			messageType, err := detectType2(service, message, p)
+			if err != nil {
+				return "", err
+			}
+			payload := map[string]interface{}{
+				"service":   service.Name,
+				"message":   strcase.UpperCamelCase(messageType[0]),
+				"parameter": strcase.UpperCamelCase(p),
+			}
+			o += runTemplate("objRequestAttr", objRequestAttr, payload) + "{\n"
+			for _, p := range propertyNames(service, messageType[0], metaData.Value.Properties) {
+				va, ok := value[p]
+				if !ok {
+					continue
+				}
+				attr, err := traverse(p, messageType[0], metaData.Value.Properties[p], va)
+				if err != nil {
+					return "", err
+				}
+				o += attr + ",\n"
+			}
+			o += "}"
+		default:
+			return "", fmt.Errorf("example uses %v of unknown type %q", p, metaData.Value.Type)
+		}
+		return o, nil
+	}
+
+	output := []string{}
+
+	endpointSchema, ok := schemas[endpoint]
+	if !ok {
+		return "", fmt.Errorf("endpoint %v doesn't exist", endpoint)
+	}
+
+	// loop through endpoint properties in the order of the proto
+	properties := endpointSchema.Value.Properties
+	for _, p := range propertyNames(service, endpoint, properties) {
+		// we ignore property that is not included in the example
+		attrValue, ok := exa[p]
+		if !ok {
+			continue
+		}
+
+		o, err := traverse(p, endpoint, properties[p], attrValue)
+		if err != nil {
+			return "", err
+		}
+		output = append(output, o+",")
+	}
+
+	return strings.Join(output, "\n"), nil
+}
+
+// goWellKnownTypes are the Go types of single values of the well-known types
+var goWellKnownTypes = map[string]string{
+	"JSON":        "map[string]interface{}",
+	"ANY":         "map[string]interface{}",
+	"VALUE":       "interface{}",
+	"LISTVALUE":   "[]interface{}",
+	"EMPTY":       "struct{}",
+	"TIMESTAMP":   "time.Time",
+	"DURATION":    "Duration",
+	"FIELDMASK":   "FieldMask",
+	"DOUBLEVALUE": "float64",
+	"FLOATVALUE":  "float32",
+	"INT64VALUE":  "int64",
+	"UINT64VALUE": "uint64",
+	"INT32VALUE":  "int32",
+	"UINT32VALUE": "uint32",
+	"BOOLVALUE":   "bool",
+	"STRINGVALUE": "string",
+	"BYTESVALUE":  "[]byte",
+}
+
+// goWrapperConstructors are the helpers returning a pointer to a value, for
+// the wrapper fields
+var goWrapperConstructors = map[string]string{
+	"DOUBLEVALUE": "Float64",
+	"FLOATVALUE":  "Float32",
+	"INT64VALUE":  "Int64",
+	"UINT64VALUE": "Uint64",
+	"INT32VALUE":  "Int32",
+	"UINT32VALUE": "Uint32",
+	"BOOLVALUE":   "Bool",
+	"STRINGVALUE": "String",
+}
+
+// goWellKnownType returns the Go type of a field of a well-known type.
+// Timestamps, empty messages and wrappers are pointers so unset fields are
+// left out, as their zero values are valid values.
+func goWellKnownType(wkt string, repeated bool) string {
+	t := goWellKnownTypes[wkt]
+	if repeated {
+		return "[]" + t
+	}
+	if _, ok := goWrapperConstructors[wkt]; ok || wkt == "TIMESTAMP" || wkt == "EMPTY" {
+		return "*" + t
+	}
+	return t
+}
+
+// goWellKnownExample renders the value of a field of a well-known type
+// from its JSON encoding in an example, using the helpers of the service
+// package for the types that need one
+func goWellKnownExample(serviceName, wkt string, repeated bool, value interface{}) (string, error) {
+	if !repeated {
+		return goWellKnownValue(serviceName, wkt, value, true)
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return "", fmt.Errorf("should be an array, got %v", value)
+	}
+	t := goWellKnownTypes[wkt]
+	if wkt == "DURATION" || wkt == "FIELDMASK" {
+		t = serviceName + "." + t
+	}
+	values := []string{}
+	for _, item := range items {
+		v, err := goWellKnownValue(serviceName, wkt, item, false)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, v)
+	}
+	if wkt == "TIMESTAMP" {
+		// see goExampleHelpersTemplate
+		return fmt.Sprintf("mustParseTimes(%v)", strings.Join(values, ", ")), nil
+	}
+	return fmt.Sprintf("[]%v{%v}", t, strings.Join(values, ", ")), nil
+}
+
+// goWellKnownValue renders a single value of a well-known type, as a
+// pointer for the types goWellKnownType makes pointers when scalar is set
+func goWellKnownValue(serviceName, wkt string, value interface{}, scalar bool) (string, error) {
+	str, isString := value.(string)
+	switch wkt {
+	case "TIMESTAMP":
+		if !isString {
+			return "", fmt.Errorf("should be an RFC 3339 timestamp, got %v", value)
+		}
+		if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+			return "", fmt.Errorf("should be an RFC 3339 timestamp: %v", err)
+		}
+		if !scalar {
+			return fmt.Sprintf("%q", str), nil
+		}
+		return fmt.Sprintf("mustParseTime(%q)", str), nil
+	case "DURATION":
+		d, err := time.ParseDuration(str)
+		if !isString || err != nil || !strings.HasSuffix(str, "s") {
+			return "", fmt.Errorf("should be a duration in seconds like \"1.5s\", got %v", value)
+		}
+		return fmt.Sprintf("%v.Duration(%d)", serviceName, d), nil
+	case "FIELDMASK":
+		if !isString {
+			return "", fmt.Errorf("should be comma separated paths, got %v", value)
+		}
+		paths := []string{}
+		for _, path := range strings.Split(str, ",") {
+			if path != "" {
+				paths = append(paths, fmt.Sprintf("%q", path))
+			}
+		}
+		return fmt.Sprintf("%v.FieldMask{%v}", serviceName, strings.Join(paths, ", ")), nil
+	case "EMPTY":
+		if scalar {
+			return "&struct{}{}", nil
+		}
+		return "struct{}{}", nil
+	case "BYTESVALUE":
+		b, err := base64.StdEncoding.DecodeString(str)
+		if !isString || err != nil {
+			return "", fmt.Errorf("should be base64 encoded bytes, got %v", value)
+		}
+		return fmt.Sprintf("[]byte(%q)", b), nil
+	case "JSON", "ANY", "VALUE", "LISTVALUE":
+		return goLiteral(value), nil
+	}
+
+	// wrappers
+	var v string
+	switch wkt {
+	case "STRINGVALUE":
+		if !isString {
+			return "", fmt.Errorf("should be a string, got %v", value)
+		}
+		v = fmt.Sprintf("%q", str)
+	case "BOOLVALUE":
+		b, ok := value.(bool)
+		if !ok {
+			return "", fmt.Errorf("should be a boolean, got %v", value)
+		}
+		v = fmt.Sprint(b)
+	default:
+		// numbers, 64 bit integers may be strings as they're encoded
+		switch n := value.(type) {
+		case float64:
+			v = strconv.FormatFloat(n, 'f', -1, 64)
+		case string:
+			if _, err := strconv.ParseFloat(n, 64); err != nil {
+				return "", fmt.Errorf("should be a number, got %q", n)
+			}
+			v = n
+		default:
+			return "", fmt.Errorf("should be a number, got %v", value)
+		}
+	}
+	if !scalar {
+		return v, nil
+	}
+	return fmt.Sprintf("%v.%v(%v)", serviceName, goWrapperConstructors[wkt], v), nil
+}
+
+// goLiteral renders a decoded JSON value as a Go literal of the types
+// encoding/json decodes it to
+func goLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return fmt.Sprintf("%q", v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := []string{}
+		for _, item := range v {
+			items = append(items, goLiteral(item))
+		}
+		return "[]interface{}{" + strings.Join(items, ", ") + "}"
+	case map[string]interface{}:
+		fields := []string{}
+		for _, key := range sortedKeys(v) {
+			fields = append(fields, fmt.Sprintf("%q: %v", key, goLiteral(v[key])))
+		}
+		return "map[string]interface{}{" + strings.Join(fields, ", ") + "}"
+	}
+	return fmt.Sprint(value)
+}
+
+// goImports returns the imports the helpers of the well-known types and the
+// oneof validations of a service need, one per line
+func goImports(service Service) string {
+	used := usedWellKnownTypes(service)
+	imports := map[string]bool{}
+	if len(oneofMessages(service)) > 0 {
+		imports["fmt"] = true
+	}
+	if len(usedInt64Values(service)) > 0 {
+		imports["encoding/json"] = true
+		imports["strconv"] = true
+	}
+	if used["TIMESTAMP"] {
+		imports["time"] = true
+	}
+	if used["DURATION"] {
+		imports["encoding/json"] = true
+		imports["strconv"] = true
+		imports["time"] = true
+	}
+	if used["FIELDMASK"] {
+		imports["encoding/json"] = true
+		imports["strings"] = true
+	}
+	ret := []string{}
+	for i := range imports {
+		ret = append(ret, fmt.Sprintf("\t%q\n", i))
+	}
+	sort.Strings(ret)
+	return strings.Join(ret, "")
+}
+
+// goWellKnownHelpers returns the types and functions of the well-known
+// types used by a service, along with the constructors of its optional
+// fields
+func goWellKnownHelpers(service Service) (string, error) {
+	used := usedWellKnownTypes(service)
+	names := map[string]bool{}
+	for wkt, name := range goWrapperConstructors {
+		if used[wkt] {
+			names[name] = true
+		}
+	}
+	// the same helpers set the optional fields, e.g. Int64 for an *int64
+	for t := range goOptionalTypes(service) {
+		names[strings.Title(t)] = true
+	}
+	wrappers := []string{}
+	for name := range names {
+		wrappers = append(wrappers, name)
+	}
+	sort.Strings(wrappers)
+	types := map[string]string{}
+	for wkt, name := range goWrapperConstructors {
+		types[name] = goWellKnownTypes[wkt]
+	}
+	b, err := render("goWellKnown"+service.Name, goWellKnownTemplate, map[string]interface{}{
+		"used":     used,
+		"wrappers": wrappers,
+		"types":    types,
+	})
+	return string(b), err
+}
+
+// goOptionalType returns the Go type of a proto3 optional field of a scalar
+// type when the optional config is pointer, the field is then a pointer to
+// it. It's empty for other fields and for enums, which are sent when set as
+// none of their values is empty.
+func goOptionalType(service Service, message, field string, schema *openapi3.Schema) string {
+	if service.config.Optional != "pointer" || !optionalField(service, message, field) {
+		return ""
+	}
+	if enum, _ := enumField(service, message, field); enum != "" {
+		return ""
+	}
+	switch schema.Type {
+	case "string":
+		return "string"
+	case "boolean":
+		return "bool"
+	case "number":
+		return goNumberTypes[schema.Format]
+	}
+	return ""
+}
+
+// goNumberTypes are the Go types of the formats of numbers
+var goNumberTypes = map[string]string{
+	"int32":  "int32",
+	"int64":  "int64",
+	"uint32": "uint32",
+	"uint64": "uint64",
+	"float":  "float32",
+	"double": "float64",
+}
+
+// goOptionalTypes returns the Go types of the optional fields of the schemas
+// of a service generated as pointers, see goOptionalType
+func goOptionalTypes(service Service) map[string]bool {
+	ret := map[string]bool{}
+	for name, schema := range service.Spec.Components.Schemas {
+		if schema.Value == nil {
+			continue
+		}
+		for p, prop := range schema.Value.Properties {
+			if prop.Value == nil {
+				continue
+			}
+			if t := goOptionalType(service, name, p, prop.Value); t != "" {
+				ret[t] = true
+			}
+		}
+	}
+	return ret
+}
+
+// goEnumConstant returns the constant of an enum value in an example, the
+// quoted value if the enum has no such value
+func goEnumConstant(service Service, enum string, value interface{}) string {
+	for _, e := range serviceEnums(service) {
+		if e.Name != enum {
+			continue
+		}
+		for _, v := range e.Values {
+			if v.Name == value {
+				return service.Name + "." + e.Name + v.Short
+			}
+		}
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// goEnums returns the declarations of the enums used by a service, typed
+// strings with a constant per value
+func goEnums(service Service) (string, error) {
+	b, err := render("goEnums"+service.Name, goEnumTemplate, map[string]interface{}{
+		"enums": serviceEnums(service),
+	})
+	return string(b), err
+}
+
+// goOneofs returns the Validate methods of the types of a service with
+// oneofs, checking at most one field of each oneof is set
+func goOneofs(service Service) (string, error) {
+	type check struct {
+		Set    string
+		Fields string
+	}
+	types := []map[string]interface{}{}
+	for _, name := range oneofMessages(service) {
+		fieldTypes, err := goFieldTypes(service, name)
+		if err != nil {
+			return "", err
+		}
+		checks := []check{}
+		for _, oo := range messageOneofs(service, name) {
+			set := []string{}
+			for _, field := range oo.fields {
+				goName := strcase.UpperCamelCase(field.GetName())
+				set = append(set, goIsSet(fieldTypes[goName], "m."+goName))
+			}
+			checks = append(checks, check{
+				Set:    strings.Join(set, ", "),
+				Fields: oo.names(func(f string) string { return f }),
+			})
+		}
+		types = append(types, map[string]interface{}{
+			"name":   strings.Title(name),
+			"checks": checks,
+		})
+	}
+	b, err := render("goOneofs"+service.Name, goOneofTemplate, map[string]interface{}{
+		"types": types,
+	})
+	return string(b), err
+}
+
+// goFieldTypes returns the Go types of the fields of a message by their Go
+// names, as schemaToType emits them
+func goFieldTypes(service Service, typeName string) (map[string]string, error) {
+	def, err := (&goG{}).schemaToType(service, typeName, service.Spec.Components.Schemas)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]string{}
+	for _, line := range strings.Split(def, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] == "//" {
+			continue
+		}
+		ret[fields[0]] = fields[1]
+	}
+	return ret, nil
+}
+
+// goIsSet returns the condition a field of a oneof is set, the zero value
+// of its Go type means it's not
+func goIsSet(goType, value string) string {
+	switch {
+	case goType == "bool":
+		return value
+	case strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"),
+		strings.HasPrefix(goType, "map["), goType == "interface{}", goType == "FieldMask":
+		return value + " != nil"
+	case goType == "Duration", strings.HasPrefix(goType, "int"),
+		strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return value + " != 0"
+	}
+	// strings, bytes and enums
+	return value + ` != ""`
+}
+
+// goInt64Keys are the Go types of the keys of maps, by the type detectType2
+// names them with
+var goInt64Keys = map[string]string{
+	"STRING":   "string",
+	"BOOL":     "bool",
+	"INT32":    "int32",
+	"SINT32":   "int32",
+	"SFIXED32": "int32",
+	"UINT32":   "uint32",
+	"FIXED32":  "uint32",
+	"INT64":    "int64",
+	"SINT64":   "int64",
+	"SFIXED64": "int64",
+	"UINT64":   "uint64",
+	"FIXED64":  "uint64",
+}
+
+// goInt64Type returns the name of the type of a list of 64 bit integers,
+// e.g. Int64s, or of a map of them when key is set, e.g. StringInt64Map
+func goInt64Type(kind, key string) string {
+	if key == "" {
+		return strings.Title(strings.ToLower(kind)) + "s"
+	}
+	return strings.Title(goInt64Keys[key]) + strings.Title(strings.ToLower(kind)) + "Map"
+}
+
+// goInt64Literal renders a 64 bit integer of an example, given as a number
+// or as a string like it's encoded
+func goInt64Literal(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case string:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+				return "", fmt.Errorf("%q is not a 64 bit integer", v)
+			}
+		}
+		return v, nil
+	}
+	return "", fmt.Errorf("%v is not a 64 bit integer", value)
+}
+
+// goInt64Helpers returns the list and map types of 64 bit integers used by
+// a service, with the JSON marshalers encoding the integers as strings
+func goInt64Helpers(service Service) (string, error) {
+	lists := []map[string]string{}
+	maps := []map[string]string{}
+	for kv := range usedInt64Values(service) {
+		kind, key := kv[0], kv[1]
+		t := map[string]string{
+			"name": goInt64Type(kind, key),
+			"type": strings.ToLower(kind),
+			// strconv.ParseInt or ParseUint
+			"func": strings.Title(strings.TrimSuffix(strings.ToLower(kind), "64")),
+			"key":  goInt64Keys[key],
+		}
+		if key == "" {
+			lists = append(lists, t)
+		} else {
+			maps = append(maps, t)
+		}
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i]["name"] < lists[j]["name"] })
+	sort.Slice(maps, func(i, j int) bool { return maps[i]["name"] < maps[j]["name"] })
+	b, err := render("goInt64"+service.Name, goInt64Template, map[string]interface{}{
+		"lists": lists,
+		"maps":  maps,
+	})
+	return string(b), err
+}
+
+// goExampleImports returns the imports the helpers of an example need on
+// top of the ones of every example, given its rendered request
+func goExampleImports(request string) string {
+	if strings.Contains(request, "mustParseTime") {
+		return "\t\"time\"\n"
+	}
+	return ""
+}
+
+// goExampleHelpers returns the helpers an example uses, given its rendered
+// request, see goExampleHelpersTemplate
+func goExampleHelpers(request string) (string, error) {
+	b, err := render("goExampleHelpers", goExampleHelpersTemplate, map[string]interface{}{
+		"time": strings.Contains(request, "mustParseTime"),
+	})
+	return string(b), err
+}
@@ -0,0 +1,129 @@
+package gen
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// goClientStub stands in for the client package the generated clients
+// import, the generated code only needs to compile against it
+const goClientStub = `package client
+
+type Options struct{ Token string }
+
+type Client struct{}
+
+type Stream struct{}
+
+func NewClient(o *Options) *Client { return &Client{} }
+
+func (c *Client) Call(service, endpoint string, req, rsp interface{}) error { return nil }
+
+func (c *Client) Stream(service, endpoint string, req interface{}) (*Stream, error) {
+	return &Stream{}, nil
+}
+
+func (s *Stream) Recv(v interface{}) error { return nil }
+`
+
+// generateGoClient generates the Go client of a service of testdata from
+// its proto into a module along with a stub client package, returning the
+// folder of the module
+func generateGoClient(t *testing.T, serviceName string, cfg Config) string {
+	t.Helper()
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+	dir := t.TempDir()
+	// the generator writes the cache and manifest next to the services
+	root := filepath.Join(dir, "root")
+	if err := copyDir(filepath.Join("testdata", serviceName), filepath.Join(root, serviceName)); err != nil {
+		t.Fatal(err)
+	}
+	module := filepath.Join(dir, "go")
+	err := Generate(context.Background(), Options{
+		Target:       "go",
+		Config:       cfg,
+		Root:         root,
+		ClientPath:   module,
+		ExamplesPath: filepath.Join(dir, "examples"),
+		Services:     []string{serviceName},
+		NoMake:       true,
+		FromProto:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"go.mod":           "module " + cfg.ImportPath + "\n\ngo 1.17\n",
+		"client/client.go": goClientStub,
+	}
+	var out DiskOutput
+	for name, content := range files {
+		if err := out.WriteFile(filepath.Join(module, name), []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return module
+}
+
+// runGo runs the go command in dir, failing the test with its output
+func runGo(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go %v: %v\n%s", args, err, out)
+	}
+}
+
+func copyDir(from, to string) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var out DiskOutput
+		return out.WriteFile(filepath.Join(to, rel), b)
+	})
+}
+
+// TestGoOneofsCompile builds the client of a service whose oneofs have
+// members of every kind, with both ways of generating optional fields
+func TestGoOneofsCompile(t *testing.T) {
+	for _, optional := range OptionalTypes {
+		t.Run(optional, func(t *testing.T) {
+			cfg := DefaultConfig("go")
+			cfg.Optional = optional
+			module := generateGoClient(t, "pay", cfg)
+			runGo(t, module, "vet", "./...")
+		})
+	}
+}
+
+// TestGoInt64Marshalers round trips the 64 bit integers of the lists and
+// maps of the ledger service through JSON, see testdata/go/int64_test.go
+func TestGoInt64Marshalers(t *testing.T) {
+	module := generateGoClient(t, "ledger", DefaultConfig("go"))
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "go", "int64_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out DiskOutput
+	if err := out.WriteFile(filepath.Join(module, "ledger", "int64_test.go"), b); err != nil {
+		t.Fatal(err)
+	}
+	runGo(t, module, "test", "./...")
+}
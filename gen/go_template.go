@@ -0,0 +1,352 @@
+package gen
+
+const goIndexTemplate = `package m3o
+import(
+	{{ range $service := .services }}"{{ $.config.ImportPath }}/{{ $service.Name}}"
+{{ end }}
+)
+func New(token string) *Client {
+	return &Client{
+		token: token,
+		{{ range $service := .services }}
+		{{ title $service.Name }}: {{ $service.Name }}.New{{ title $service.Name}}Service(token),{{end}}
+	}
+}
+type Client struct {
+	token string
+{{ range $service := .services }}
+	{{ title $service.Name }} {{ $service.Name }}.{{ title $service.Name }}{{end}}
+}
+`
+
+const goServiceTemplate = `{{ $service := .service }}package {{ $service.Name }}
+
+import(
+	"{{ .config.ImportPath }}/client"
+{{ goImports $service }})
+
+type {{ title $service.Name }} interface {
+{{ range $key, $req := $service.Spec.Components.RequestBodies }}{{ $reqType := requestType $key }}{{ $endpointName := requestTypeToEndpointName $key}}	{{ $endpointName }}(*{{ requestType $key }}) (*{{ requestTypeToResponseType $key }}{{ if isStream $service.Spec $service.Name $reqType }}Stream{{end}}, error)
+{{end}}
+}
+func New{{ title $service.Name }}Service(token string) *{{ title $service.Name }}Service {
+	return &{{ title $service.Name }}Service{
+		client: client.NewClient(&client.Options{
+			Token: token,
+		}),
+	}
+}
+
+type {{ title $service.Name }}Service struct {
+	client *client.Client
+}
+
+{{ range $key, $req := $service.Spec.Components.RequestBodies }}{{ $reqType := requestType $key }}
+{{ $endpointName := requestTypeToEndpointName $key}}{{ if endpointComment $endpointName $service.Spec.Components.Schemas }}{{ endpointComment $endpointName $service.Spec.Components.Schemas }}{{ end }}func (t *{{ title $service.Name }}Service) {{ $endpointName }}(request *{{ requestType $key }}) (*{{ requestTypeToResponseType $key }}{{ if isStream $service.Spec $service.Name $reqType }}Stream{{ end }}, error) {
+	{{ if isStream $service.Spec $service.Name $reqType }}stream, err := t.client.Stream("{{ $service.Name }}", "{{ requestTypeToEndpointPath $key}}", request)
+	if err != nil {
+			return nil, err
+	}
+	return &{{ requestTypeToResponseType $key }}Stream{
+			stream: stream,
+	}, nil
+	{{ end }}
+	{{ if isNotStream $service.Spec $service.Name $reqType }}rsp := &{{ requestTypeToResponseType $key }}{}
+	return rsp, t.client.Call("{{ $service.Name }}", "{{ requestTypeToEndpointPath $key}}", request, rsp)
+	{{ end }}
+}
+
+{{ if isStream $service.Spec $service.Name $reqType }}
+type {{ requestTypeToResponseType $key }}Stream struct {
+	stream *client.Stream
+}
+
+func (t *{{ requestTypeToResponseType $key }}Stream) Recv() (*{{ requestTypeToResponseType $key }}, error) {
+	var rsp {{ requestTypeToResponseType $key }}
+	if err := t.stream.Recv(&rsp); err != nil {
+			return nil, err
+	}
+	return &rsp, nil
+}
+{{ end }}
+{{ end }}
+
+
+{{ range $typeName, $schema := $service.Spec.Components.Schemas }}
+type {{ title $typeName }} struct {{ "{" }}
+{{ recursiveTypeDefinitionGo $service $typeName $service.Spec.Components.Schemas }}{{ "}" }}
+{{end}}
+{{ goEnums $service }}{{ goOneofs $service }}{{ goWellKnownHelpers $service }}{{ goInt64Helpers $service }}`
+
+// goInt64Template renders the list and map types of 64 bit integers used
+// by a service, see goInt64Helpers
+const goInt64Template = `{{ range $list := .lists }}
+// {{ $list.name }} is a list of {{ $list.type }} encoded in JSON as strings, like protobuf
+// encodes 64 bit integers
+type {{ $list.name }} []{{ $list.type }}
+
+func (l {{ $list.name }}) MarshalJSON() ([]byte, error) {
+	s := make([]string, len(l))
+	for i, v := range l {
+		s[i] = strconv.Format{{ $list.func }}(v, 10)
+	}
+	return json.Marshal(s)
+}
+
+func (l *{{ $list.name }}) UnmarshalJSON(b []byte) error {
+	var s []json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*l = nil
+		return nil
+	}
+	*l = make({{ $list.name }}, len(s))
+	for i, n := range s {
+		v, err := strconv.Parse{{ $list.func }}(string(n), 10, 64)
+		if err != nil {
+			return err
+		}
+		(*l)[i] = v
+	}
+	return nil
+}
+{{ end }}{{ range $map := .maps }}
+// {{ $map.name }} is a map of {{ $map.type }} encoded in JSON as strings, like protobuf
+// encodes 64 bit integers
+type {{ $map.name }} map[{{ $map.key }}]{{ $map.type }}
+
+{{ if eq $map.key "bool" }}// encoding/json only encodes string and integer keys, bool keys are
+// converted like protobuf encodes them
+{{ end }}func (m {{ $map.name }}) MarshalJSON() ([]byte, error) {
+	s := make(map[{{ if eq $map.key "bool" }}string{{ else }}{{ $map.key }}{{ end }}]string, len(m))
+	for k, v := range m {
+		s[{{ if eq $map.key "bool" }}strconv.FormatBool(k){{ else }}k{{ end }}] = strconv.Format{{ $map.func }}(v, 10)
+	}
+	return json.Marshal(s)
+}
+
+func (m *{{ $map.name }}) UnmarshalJSON(b []byte) error {
+	var s map[{{ if eq $map.key "bool" }}string{{ else }}{{ $map.key }}{{ end }}]json.Number
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*m = nil
+		return nil
+	}
+	*m = make({{ $map.name }}, len(s))
+	for k, n := range s {
+		v, err := strconv.Parse{{ $map.func }}(string(n), 10, 64)
+		if err != nil {
+			return err
+		}
+{{ if eq $map.key "bool" }}		key, err := strconv.ParseBool(k)
+		if err != nil {
+			return err
+		}
+		(*m)[key] = v
+{{ else }}		(*m)[k] = v
+{{ end }}	}
+	return nil
+}
+{{ end }}`
+
+// goOneofTemplate renders the validations of the oneofs of a service, see
+// goOneofs
+const goOneofTemplate = `{{ range $type := .types }}
+// Validate checks at most one field of each oneof of {{ $type.name }} is set
+func (m *{{ $type.name }}) Validate() error {
+	if m == nil {
+		return nil
+	}
+{{ range $check := $type.checks }}	if oneofSet({{ $check.Set }}) > 1 {
+		return fmt.Errorf("{{ $type.name }}: only one of {{ $check.Fields }} may be set")
+	}
+{{ end }}	return nil
+}
+{{ end }}{{ if .types }}
+// oneofSet counts the fields of a oneof that are set
+func oneofSet(set ...bool) int {
+	n := 0
+	for _, s := range set {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+{{ end }}`
+
+// goEnumTemplate renders the enums used by a service, see goEnums
+const goEnumTemplate = `{{ range $enum := .enums }}
+{{ commentLines "// " $enum.Comment }}type {{ $enum.Name }} string
+
+const (
+{{ range $value := $enum.Values }}{{ commentLines "\t// " $value.Comment }}	{{ $enum.Name }}{{ $value.Short }} {{ $enum.Name }} = "{{ $value.Name }}"
+{{ end }})
+{{ end }}`
+
+// goWellKnownTemplate renders the helpers of the well-known types used by a
+// service, see goWellKnownHelpers
+const goWellKnownTemplate = `{{ if .used.DURATION }}
+// Duration is a protobuf duration, encoded in JSON as seconds with an "s"
+// suffix, e.g. "1.5s"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatFloat(time.Duration(d).Seconds(), 'f', -1, 64) + "s")
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*d = 0
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+{{ end }}{{ if .used.FIELDMASK }}
+// FieldMask is a protobuf field mask, the paths of the fields to use,
+// encoded in JSON as a comma separated string
+type FieldMask []string
+
+func (m FieldMask) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(m, ","))
+}
+
+func (m *FieldMask) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*m = nil
+	if s != "" {
+		*m = strings.Split(s, ",")
+	}
+	return nil
+}
+{{ end }}{{ range $name := .wrappers }}
+// {{ $name }} returns a pointer to v, for the optional {{ index $.types $name }} fields
+func {{ $name }}(v {{ index $.types $name }}) *{{ index $.types $name }} {
+	return &v
+}
+{{ end }}`
+
+const goExampleTemplate = `{{ $service := .service }}{{ $request := goExampleRequest $service .endpoint $service.Spec.Components.Schemas .example.Request }}package main
+
+import(
+	"fmt"
+	"os"
+{{ goExampleImports $request }}
+	"{{ .config.ImportPath }}"
+	"{{ .config.ImportPath }}/{{ $service.Name}}"
+)
+
+func main() {
+	client := m3o.New(os.Getenv("{{ .config.TokenEnv }}"))
+	{{ $reqType := requestType .endpoint }}{{ if isNotStream $service.Spec $service.Name $reqType }}rsp, err := client.{{ title $service.Name }}.{{ title .endpoint }}(&{{ $service.Name }}.{{ title .endpoint }}Request{
+		{{ $request }}
+	})
+	fmt.Println(rsp, err){{ end -}}
+	{{ if isStream $service.Spec $service.Name $reqType }}stream, err := client.{{ title $service.Name }}.{{ title .endpoint }}(&{{ $service.Name }}.{{ title .endpoint }}Request{
+		{{ $request }}
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for {
+			rsp, err := stream.Recv()
+			if err != nil {
+					fmt.Println(err)
+					return
+			}
+
+			fmt.Println(rsp)
+	}{{ end }}
+}{{ goExampleHelpers $request }}`
+
+// goExampleHelpersTemplate renders the helpers of the examples setting
+// fields that can't be set with a literal
+const goExampleHelpersTemplate = `{{ if .time }}
+
+// mustParseTime parses an RFC 3339 timestamp, for the timestamp fields
+func mustParseTime(rfc3339 string) *time.Time {
+	t, err := time.Parse(time.RFC3339Nano, rfc3339)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+// mustParseTimes parses RFC 3339 timestamps, for the repeated timestamp
+// fields
+func mustParseTimes(rfc3339 ...string) []time.Time {
+	ret := []time.Time{}
+	for _, s := range rfc3339 {
+		ret = append(ret, *mustParseTime(s))
+	}
+	return ret
+}{{ end }}`
+
+const goReadmeTopTemplate = `{{ $service := .service }}# {{ title $service.Name }}
+
+An [m3o.com](https://m3o.com) API. For example usage see [m3o.com/{{ $service.Name }}/api](https://m3o.com/{{ $service.Name }}/api).
+
+Endpoints:
+
+`
+
+const goReadmeBottomTemplate = `{{ $service := .service }}{{ $request := goExampleRequest $service .endpoint $service.Spec.Components.Schemas .example.Request }}## {{ title .endpoint }}
+
+{{ endpointDescription .endpoint $service.Spec.Components.Schemas }}
+
+[https://m3o.com/{{ $service.Name }}/api#{{ title .endpoint}}](https://m3o.com/{{ $service.Name }}/api#{{ title .endpoint}})
+
+` + "```" + `go
+package example
+
+import(
+	"fmt"
+	"os"
+{{ goExampleImports $request }}
+	"{{ .config.ImportPath }}/{{ $service.Name}}"
+)
+
+{{ if endpointComment .endpoint $service.Spec.Components.Schemas }}{{ endpointComment .endpoint $service.Spec.Components.Schemas }}{{ end }}func {{ .funcName }}() {
+	{{ $service.Name }}Service := {{ $service.Name }}.New{{ title $service.Name }}Service(os.Getenv("{{ .config.TokenEnv }}"))
+	{{ $reqType := requestType .endpoint }}{{ if isNotStream $service.Spec $service.Name $reqType }}rsp, err := {{ $service.Name }}Service.{{ title .endpoint }}(&{{ $service.Name }}.{{ title .endpoint }}Request{
+		{{ $request }}
+	})
+	fmt.Println(rsp, err){{ end }}
+	{{ if isStream $service.Spec $service.Name $reqType }}stream, err := {{ $service.Name }}Service.{{ title .endpoint }}(&{{ $service.Name }}.{{ title .endpoint }}Request{
+		{{ $request }}
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for {
+			rsp, err := stream.Recv()
+			if err != nil {
+					fmt.Println(err)
+					return
+			}
+
+			fmt.Println(rsp)
+	}{{ end }}
+}{{ goExampleHelpers $request }}
+` + "```" + `
+`
@@ -0,0 +1,76 @@
+package gen
+
+import (
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	dpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// int64Kind returns "INT64" or "UINT64" for a single value of a field
+// holding a 64 bit integer, including the 64 bit wrappers, empty for other
+// fields. 64 bit integers are encoded as strings in JSON as they don't fit
+// in a JavaScript number.
+func int64Kind(field *desc.FieldDescriptor) string {
+	switch field.GetType() {
+	case dpb.FieldDescriptorProto_TYPE_INT64, dpb.FieldDescriptorProto_TYPE_SINT64, dpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return "INT64"
+	case dpb.FieldDescriptorProto_TYPE_UINT64, dpb.FieldDescriptorProto_TYPE_FIXED64:
+		return "UINT64"
+	}
+	switch wellKnownTypeOf(field) {
+	case "INT64VALUE":
+		return "INT64"
+	case "UINT64VALUE":
+		return "UINT64"
+	}
+	return ""
+}
+
+// int64Values returns the kind of the 64 bit integers, see int64Kind, of
+// the items of a repeated field or the values of a map, along with the type
+// of the keys of maps as detectType2 names it. The kind is empty for other
+// fields, including the single 64 bit integers.
+func int64Values(service Service, message, field string) (string, string) {
+	files, err := parseProto(service)
+	if err != nil {
+		return "", ""
+	}
+	msgDesc := files.message(message)
+	if msgDesc == nil {
+		return "", ""
+	}
+	fieldDesc := msgDesc.FindFieldByName(field)
+	if fieldDesc == nil || !fieldDesc.IsRepeated() {
+		return "", ""
+	}
+	if fieldDesc.IsMap() {
+		key := strings.TrimPrefix(fieldDesc.GetMapKeyType().GetType().String(), "TYPE_")
+		return int64Kind(fieldDesc.GetMapValueType()), key
+	}
+	return int64Kind(fieldDesc), ""
+}
+
+// usedInt64Values returns the kinds of the 64 bit integers of the repeated
+// fields, with an empty key, and of the maps, with the type of their keys,
+// of the messages of a service
+func usedInt64Values(service Service) map[[2]string]bool {
+	ret := map[[2]string]bool{}
+	for _, m := range serviceMessages(service) {
+		for _, field := range m.GetFields() {
+			if !field.IsRepeated() {
+				continue
+			}
+			if field.IsMap() {
+				if kind := int64Kind(field.GetMapValueType()); kind != "" {
+					ret[[2]string{kind, strings.TrimPrefix(field.GetMapKeyType().GetType().String(), "TYPE_")}] = true
+				}
+				continue
+			}
+			if kind := int64Kind(field); kind != "" {
+				ret[[2]string{kind, ""}] = true
+			}
+		}
+	}
+	return ret
+}
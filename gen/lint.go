@@ -0,0 +1,257 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/camelcase"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Severity is how a lint rule is reported
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	// the rule isn't checked
+	SeverityOff Severity = "off"
+)
+
+// LintRule is a convention the templates rely on
+type LintRule struct {
+	Name        string
+	Description string
+	Default     Severity
+}
+
+// LintRules are the rules checked by Lint
+var LintRules = []LintRule{
+	{Name: "endpoint_description", Description: "every endpoint request has a description, used as the doc comment of the client method", Default: SeverityWarning},
+	{Name: "field_description", Description: "every field has a description", Default: SeverityWarning},
+	{Name: "request_response", Description: "every <Endpoint>Request has a matching <Endpoint>Response and every rpc takes and returns them", Default: SeverityError},
+	{Name: "camelcase", Description: "request bodies split into <Service><Endpoint>Request on camel case boundaries", Default: SeverityError},
+	{Name: "path", Description: "every path is /<service>/<Service>/<Endpoint> and uses the request body and schema of its endpoint", Default: SeverityError},
+	{Name: "proto", Description: "the rpcs of the proto match the paths of the spec", Default: SeverityError},
+}
+
+// LintSeverities returns the severity of every rule, the defaults with the
+// given overrides applied
+func LintSeverities(overrides map[string]string) (map[string]Severity, error) {
+	ret := map[string]Severity{}
+	for _, r := range LintRules {
+		ret[r.Name] = r.Default
+	}
+	for name, s := range overrides {
+		if _, ok := ret[name]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		switch Severity(s) {
+		case SeverityError, SeverityWarning, SeverityOff:
+			ret[name] = Severity(s)
+		case "false":
+			// an unquoted off in yaml
+			ret[name] = SeverityOff
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %v, use error, warning or off", s, name)
+		}
+	}
+	return ret, nil
+}
+
+// LintIssue is a rule broken by the spec or proto of a service
+type LintIssue struct {
+	Service  string
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%-7v %v: %v [%v]", i.Severity, i.Service, i.Message, i.Rule)
+}
+
+// Lint checks the spec of a service, and its proto when protoFile exists,
+// follows the conventions the templates rely on. The imports of the proto
+// are looked up in its folder, then in importPaths. Rules are reported with
+// the given severities, rules turned off or missing aren't checked.
+func Lint(serviceName string, spec *openapi3.Swagger, protoFile string, importPaths []string, severities map[string]Severity) []LintIssue {
+	issues := []LintIssue{}
+	report := func(rule, format string, args ...interface{}) {
+		s := severities[rule]
+		if s == "" || s == SeverityOff {
+			return
+		}
+		issues = append(issues, LintIssue{
+			Service:  serviceName,
+			Rule:     rule,
+			Severity: s,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+	schemas := spec.Components.Schemas
+	title := strings.Title(serviceName)
+
+	// request bodies, the templates derive the names of the client methods,
+	// request and response types from their keys
+	bodies := []string{}
+	for key := range spec.Components.RequestBodies {
+		bodies = append(bodies, key)
+	}
+	sort.Strings(bodies)
+	for _, key := range bodies {
+		parts := camelcase.Split(key)
+		if len(parts) < 3 || parts[len(parts)-1] != "Request" {
+			report("camelcase", "request body %v doesn't split into <Service><Endpoint>Request", key)
+			continue
+		}
+		endpoint := strings.Join(parts[1:len(parts)-1], "")
+		if parts[0] != title {
+			report("camelcase", "request body %v splits into service %v and endpoint %v, expected %v<Endpoint>Request", key, parts[0], endpoint, title)
+			continue
+		}
+		if findPath(spec, fmt.Sprintf("/%v/%v/%v", serviceName, title, endpoint)) == nil {
+			report("path", "request body %v has no path /%v/%v/%v", key, serviceName, title, endpoint)
+		}
+	}
+
+	paths := []string{}
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		if len(parts) != 3 || parts[0] != serviceName || parts[1] != title {
+			report("path", "path %v isn't /%v/%v/<Endpoint>", path, serviceName, title)
+			continue
+		}
+		endpoint := strings.Title(parts[2])
+		op := spec.Paths[path].Post
+		if op == nil {
+			report("path", "path %v has no POST operation", path)
+			continue
+		}
+
+		request, response := endpoint+"Request", endpoint+"Response"
+		if s := schemas[request]; s == nil || s.Value == nil {
+			report("request_response", "endpoint %v has no schema %v", endpoint, request)
+		} else if strings.TrimSpace(s.Value.Description) == "" {
+			report("endpoint_description", "endpoint %v has no description, add a comment to the %v message", endpoint, request)
+		}
+		if s := schemas[response]; s == nil || s.Value == nil {
+			report("request_response", "endpoint %v has no schema %v", endpoint, response)
+		}
+
+		if op.RequestBody == nil {
+			report("path", "path %v has no request body", path)
+		} else {
+			if body := "#/components/requestBodies/" + title + endpoint + "Request"; op.RequestBody.Ref != "" && op.RequestBody.Ref != body {
+				report("path", "path %v uses request body %v, expected %v", path, op.RequestBody.Ref, body)
+			}
+			if ref := jsonSchemaRef(op.RequestBody.Value); ref != "" && ref != "#/components/schemas/"+request {
+				report("path", "path %v takes %v, expected %v", path, strings.TrimPrefix(ref, "#/components/schemas/"), request)
+			}
+		}
+		if op.Responses["200"] == nil && op.Responses["stream"] == nil {
+			report("request_response", "path %v has no 200 or stream response", path)
+		}
+	}
+
+	names := []string{}
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasSuffix(name, "Request") && schemas[strings.TrimSuffix(name, "Request")+"Response"] == nil {
+			report("request_response", "%v has no matching %vResponse", name, strings.TrimSuffix(name, "Request"))
+		}
+		if strings.HasSuffix(name, "Response") && schemas[strings.TrimSuffix(name, "Response")+"Request"] == nil {
+			report("request_response", "%v has no matching %vRequest", name, strings.TrimSuffix(name, "Response"))
+		}
+		s := schemas[name].Value
+		if s == nil {
+			continue
+		}
+		props := []string{}
+		for prop := range s.Properties {
+			props = append(props, prop)
+		}
+		sort.Strings(props)
+		for _, prop := range props {
+			if p := s.Properties[prop]; p.Value != nil && strings.TrimSpace(p.Value.Description) == "" {
+				report("field_description", "field %v.%v has no description", name, prop)
+			}
+		}
+	}
+
+	if _, err := os.Stat(protoFile); err == nil {
+		lintProto(serviceName, spec, protoFile, importPaths, report)
+	}
+	return issues
+}
+
+// lintProto checks the rpcs of the proto of a service match its spec
+func lintProto(serviceName string, spec *openapi3.Swagger, protoFile string, importPaths []string, report func(rule, format string, args ...interface{})) {
+	fd, err := parseProtoFile(protoFile, importPaths)
+	if err != nil {
+		report("proto", "%v", err)
+		return
+	}
+	if len(fd.GetServices()) == 0 {
+		report("proto", "no service found in %v", protoFile)
+		return
+	}
+	name := filepath.Base(protoFile)
+	rpcs := map[string]bool{}
+	for _, method := range fd.GetServices()[0].GetMethods() {
+		rpc := method.GetName()
+		rpcs[strings.ToLower(rpc)] = true
+		if in := method.GetInputType().GetName(); in != rpc+"Request" {
+			report("request_response", "rpc %v in %v takes %v, expected %vRequest", rpc, name, in, rpc)
+		}
+		if out := method.GetOutputType().GetName(); out != rpc+"Response" {
+			report("request_response", "rpc %v in %v returns %v, expected %vResponse", rpc, name, out, rpc)
+		}
+		if findPath(spec, fmt.Sprintf("/%v/%v/%v", serviceName, strings.Title(serviceName), rpc)) == nil {
+			report("proto", "rpc %v in %v has no path in the spec, is the api json out of date?", rpc, name)
+		}
+	}
+
+	paths := []string{}
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if !rpcs[strings.ToLower(path[strings.LastIndex(path, "/")+1:])] {
+			report("proto", "path %v has no rpc in %v", path, name)
+		}
+	}
+}
+
+// findPath looks up a path case insensitively like the templates do
+func findPath(spec *openapi3.Swagger, path string) *openapi3.PathItem {
+	for k, v := range spec.Paths {
+		if strings.ToLower(k) == strings.ToLower(path) {
+			return v
+		}
+	}
+	return nil
+}
+
+// jsonSchemaRef returns the schema a request body refers to
+func jsonSchemaRef(body *openapi3.RequestBody) string {
+	if body == nil {
+		return ""
+	}
+	mt := body.Content.Get("application/json")
+	if mt == nil || mt.Schema == nil {
+		return ""
+	}
+	return mt.Schema.Ref
+}
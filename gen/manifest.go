@@ -0,0 +1,192 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Manifest lists the files generated for every service of every target, so
+// the files no run produces anymore, e.g. the examples of a removed
+// endpoint, can be found and deleted. Services that aren't generated by a
+// run, because they're unchanged, not selected or failed, keep the files of
+// their previous run. It's safe for concurrent use.
+type Manifest struct {
+	path string
+
+	mu sync.Mutex
+	// files by target and service, the index files of a target are listed
+	// with an empty service name
+	Files map[string]map[string][]string `json:"files"`
+	// files generated by previous runs that no run produces anymore and
+	// that haven't been deleted yet
+	Orphaned []string `json:"orphaned,omitempty"`
+}
+
+// LoadManifest reads the manifest at path, a missing file is an empty
+// manifest. The paths of the manifest file are relative to its folder.
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{
+		path:  path,
+		Files: map[string]map[string][]string{},
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest %v: %v", path, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]map[string][]string{}
+	}
+	m.convert(func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(filepath.Dir(path), filepath.FromSlash(p))
+	})
+	return m, nil
+}
+
+// Save writes the manifest back to the file it was loaded from
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	saved := &Manifest{
+		Files:    map[string]map[string][]string{},
+		Orphaned: append([]string{}, m.Orphaned...),
+	}
+	for target, services := range m.Files {
+		saved.Files[target] = map[string][]string{}
+		for service, files := range services {
+			saved.Files[target][service] = append([]string{}, files...)
+		}
+	}
+	m.mu.Unlock()
+
+	dir := filepath.Dir(m.path)
+	saved.convert(func(p string) string {
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return p
+		}
+		return filepath.ToSlash(rel)
+	})
+	b, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(m.path, append(b, '\n'), FILE_EXECUTE_PERMISSION)
+}
+
+// convert rewrites every path of the manifest
+func (m *Manifest) convert(f func(string) string) {
+	for _, services := range m.Files {
+		for _, files := range services {
+			for i := range files {
+				files[i] = f(files[i])
+			}
+		}
+	}
+	for i := range m.Orphaned {
+		m.Orphaned[i] = f(m.Orphaned[i])
+	}
+}
+
+// Paths returns the sorted paths of all the files of the manifest, leaving
+// out the orphaned ones
+func (m *Manifest) Paths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := map[string]bool{}
+	ret := []string{}
+	for _, services := range m.Files {
+		for _, files := range services {
+			for _, path := range files {
+				if !seen[path] {
+					seen[path] = true
+					ret = append(ret, path)
+				}
+			}
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// setFiles replaces the files of a service of a target
+func (m *Manifest) setFiles(target, service string, files []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Files[target] == nil {
+		m.Files[target] = map[string][]string{}
+	}
+	m.Files[target][service] = files
+}
+
+// keepServices drops the services of a target not in services, along with
+// the files generated for them
+func (m *Manifest) keepServices(target string, services []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keep := map[string]bool{"": true}
+	for _, s := range services {
+		keep[s] = true
+	}
+	for s := range m.Files[target] {
+		if !keep[s] {
+			delete(m.Files[target], s)
+		}
+	}
+}
+
+// recorder is an Output keeping track of the files written through it
+type recorder struct {
+	Output
+
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+func newRecorder(out Output) *recorder {
+	return &recorder{
+		Output: out,
+		paths:  map[string]bool{},
+	}
+}
+
+func (r *recorder) WriteFile(path string, data []byte) error {
+	r.record(path)
+	return r.Output.WriteFile(path, data)
+}
+
+func (r *recorder) AppendFile(path string, data []byte) error {
+	r.record(path)
+	return r.Output.AppendFile(path, data)
+}
+
+func (r *recorder) record(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paths[path] = true
+}
+
+// files returns the sorted paths of the files written so far
+func (r *recorder) files() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := []string{}
+	for path := range r.paths {
+		ret = append(ret, path)
+	}
+	sort.Strings(ret)
+	return ret
+}
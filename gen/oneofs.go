@@ -0,0 +1,100 @@
+package gen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+)
+
+// oneof is a oneof group of a message, at most one of its fields is set.
+// The spec has its fields as independent fields of the message.
+type oneof struct {
+	// proto name of the oneof
+	Name    string
+	Comment string
+	// proto names of the fields, in the order of the proto
+	Fields []string
+
+	fields []*desc.FieldDescriptor
+}
+
+// messageOneofs returns the oneofs of a message in the order of the proto,
+// leaving out the ones protoc makes up for proto3 optional fields
+func messageOneofs(service Service, message string) []oneof {
+	files, err := parseProto(service)
+	if err != nil {
+		return nil
+	}
+	msgDesc := files.message(message)
+	if msgDesc == nil {
+		return nil
+	}
+	ret := []oneof{}
+	for _, o := range msgDesc.GetOneOfs() {
+		if o.IsSynthetic() {
+			continue
+		}
+		oo := oneof{
+			Name:    o.GetName(),
+			Comment: comment(o.GetSourceInfo()),
+			fields:  o.GetChoices(),
+		}
+		for _, field := range o.GetChoices() {
+			oo.Fields = append(oo.Fields, field.GetName())
+		}
+		ret = append(ret, oo)
+	}
+	return ret
+}
+
+// optionalField reports whether a field of a message is a proto3 optional
+// field, which tracks presence like the fields of a oneof
+func optionalField(service Service, message, field string) bool {
+	files, err := parseProto(service)
+	if err != nil {
+		return false
+	}
+	msgDesc := files.message(message)
+	if msgDesc == nil {
+		return false
+	}
+	fieldDesc := msgDesc.FindFieldByName(field)
+	return fieldDesc != nil && fieldDesc.IsProto3Optional()
+}
+
+// oneofOf returns the oneof a field of a message is part of, nil if it's
+// not part of one
+func oneofOf(service Service, message, field string) *oneof {
+	for _, o := range messageOneofs(service, message) {
+		for _, f := range o.Fields {
+			if f == field {
+				return &o
+			}
+		}
+	}
+	return nil
+}
+
+// names returns the names of the fields of a oneof, as named by name,
+// separated by commas
+func (o *oneof) names(name func(string) string) string {
+	names := []string{}
+	for _, f := range o.Fields {
+		names = append(names, name(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// oneofMessages returns the sorted names of the schemas of a service whose
+// messages have oneofs
+func oneofMessages(service Service) []string {
+	ret := []string{}
+	for name := range service.Spec.Components.Schemas {
+		if len(messageOneofs(service, name)) > 0 {
+			ret = append(ret, name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,100 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Output is where the generators write the files they render, paths are
+// passed as built from the paths given in Options
+type Output interface {
+	// WriteFile creates the file at path or truncates it if it exists
+	WriteFile(path string, data []byte) error
+	// AppendFile appends data to the file at path, creating it if needed
+	AppendFile(path string, data []byte) error
+}
+
+// DiskOutput writes the files straight to disk, creating folders as needed
+type DiskOutput struct{}
+
+func (DiskOutput) WriteFile(path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), FOLDER_EXECUTE_PERMISSION)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, FILE_EXECUTE_PERMISSION)
+}
+
+func (DiskOutput) AppendFile(path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), FOLDER_EXECUTE_PERMISSION)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, FILE_EXECUTE_PERMISSION)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// MemOutput keeps the rendered files in memory, it's used to look at what a
+// run would produce without touching the files on disk. Appending to a file
+// that wasn't written during the run starts from an empty file.
+type MemOutput struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func NewMemOutput() *MemOutput {
+	return &MemOutput{
+		files: map[string][]byte{},
+	}
+}
+
+func (m *MemOutput) WriteFile(path string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[path] = append([]byte{}, data...)
+	return nil
+}
+
+func (m *MemOutput) AppendFile(path string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[path] = append(m.files[path], data...)
+	return nil
+}
+
+// Paths returns the sorted paths of the files written so far
+func (m *MemOutput) Paths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ret := []string{}
+	for path := range m.files {
+		ret = append(ret, path)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Has reports whether the file at path was written
+func (m *MemOutput) Has(path string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.files[path]
+	return ok
+}
+
+// File returns the content of the file at path
+func (m *MemOutput) File(path string) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.files[path]
+}
@@ -0,0 +1,245 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jhump/protoreflect/desc"
+	dpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// protoSpec converts the proto of a service into the same openapi spec the
+// micro protoc-gen-openapi plugin generates for "make api": a POST path per
+// rpc, a request body and response per rpc and a schema per message, with
+// the proto comments as descriptions. Imports are looked up in the folder
+// of the proto, then in importPaths.
+func protoSpec(serviceName, protoFile string, importPaths []string) (*openapi3.Swagger, error) {
+	file, err := parseProtoFile(protoFile, importPaths)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.GetServices()) == 0 {
+		return nil, fmt.Errorf("no service found in %v", protoFile)
+	}
+	svc := file.GetServices()[0]
+
+	spec := &openapi3.Swagger{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:       svc.GetName(),
+			Description: comment(svc.GetSourceInfo()),
+			Version:     "1",
+		},
+		Paths: openapi3.Paths{},
+		Components: openapi3.Components{
+			Schemas:       map[string]*openapi3.SchemaRef{},
+			RequestBodies: map[string]*openapi3.RequestBodyRef{},
+			Responses: map[string]*openapi3.ResponseRef{
+				"MicroAPIError": {
+					Value: openapi3.NewResponse().
+						WithDescription("MicroAPIError").
+						WithJSONSchema(openapi3.NewObjectSchema()),
+				},
+			},
+		},
+	}
+
+	// comments of the rpcs, used for request messages without a comment
+	rpcComments := map[string]string{}
+	for _, method := range svc.GetMethods() {
+		request := svc.GetName() + method.GetName() + "Request"
+		response := svc.GetName() + method.GetName() + "Response"
+
+		spec.Components.RequestBodies[request] = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().
+				WithDescription(fmt.Sprintf("%v %v request", svc.GetName(), method.GetName())).
+				WithJSONSchemaRef(&openapi3.SchemaRef{Ref: "#/components/schemas/" + flatName(method.GetInputType())}),
+		}
+		spec.Components.Responses[response] = &openapi3.ResponseRef{
+			Value: openapi3.NewResponse().
+				WithDescription(fmt.Sprintf("%v %v response", svc.GetName(), method.GetName())).
+				WithJSONSchemaRef(&openapi3.SchemaRef{Ref: "#/components/schemas/" + flatName(method.GetOutputType())}),
+		}
+
+		responses := openapi3.Responses{
+			"default": {Ref: "#/components/responses/MicroAPIError"},
+		}
+		// streams are flagged by the response name, see isStream
+		if method.IsServerStreaming() || method.IsClientStreaming() {
+			responses["stream"] = &openapi3.ResponseRef{Ref: "#/components/responses/" + response}
+		} else {
+			responses["200"] = &openapi3.ResponseRef{Ref: "#/components/responses/" + response}
+		}
+
+		c := comment(method.GetSourceInfo())
+		// eg. "/notes/Notes/Events"
+		path := fmt.Sprintf("/%v/%v/%v", serviceName, svc.GetName(), method.GetName())
+		spec.Paths[path] = &openapi3.PathItem{
+			Post: &openapi3.Operation{
+				Summary:     fmt.Sprintf("%v.%v(%v)", svc.GetName(), method.GetName(), flatName(method.GetInputType())),
+				Description: c,
+				RequestBody: &openapi3.RequestBodyRef{Ref: "#/components/requestBodies/" + request},
+				Responses:   responses,
+			},
+		}
+
+		if c != "" {
+			rpcComments[method.GetInputType().GetFullyQualifiedName()] = c
+		}
+	}
+
+	for _, msg := range protoMessages(file) {
+		schema := messageSchema(msg, map[string]bool{})
+		if schema.Description == "" {
+			schema.Description = rpcComments[msg.GetFullyQualifiedName()]
+		}
+		spec.Components.Schemas[flatName(msg)] = openapi3.NewSchemaRef("", schema)
+	}
+
+	return spec, nil
+}
+
+// protoMessages returns the messages of a proto file, including the nested
+// ones, and the messages of imported files its messages and rpcs use, so
+// every type the generators look up has a schema
+func protoMessages(file *desc.FileDescriptor) []*desc.MessageDescriptor {
+	ret := []*desc.MessageDescriptor{}
+	seen := map[string]bool{}
+	var add func(msg *desc.MessageDescriptor)
+	add = func(msg *desc.MessageDescriptor) {
+		if seen[msg.GetFullyQualifiedName()] || msg.GetFile().GetPackage() == "google.protobuf" {
+			return
+		}
+		seen[msg.GetFullyQualifiedName()] = true
+		if !msg.IsMapEntry() {
+			ret = append(ret, msg)
+		}
+		for _, nested := range msg.GetNestedMessageTypes() {
+			add(nested)
+		}
+		for _, field := range msg.GetFields() {
+			if m := field.GetMessageType(); m != nil {
+				add(m)
+			}
+		}
+	}
+	for _, msg := range file.GetMessageTypes() {
+		add(msg)
+	}
+	// the requests and responses of the rpcs can come from imported files
+	for _, svc := range file.GetServices() {
+		for _, method := range svc.GetMethods() {
+			add(method.GetInputType())
+			add(method.GetOutputType())
+		}
+	}
+	return ret
+}
+
+// messageSchema returns the schema of a message with the schemas of the
+// messages it uses inlined, as the generators expect. parents holds the
+// messages being converted to stop recursive messages.
+func messageSchema(msg *desc.MessageDescriptor, parents map[string]bool) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	schema.Title = flatName(msg)
+	schema.Description = comment(msg.GetSourceInfo())
+
+	if parents[msg.GetFullyQualifiedName()] {
+		return schema
+	}
+	parents[msg.GetFullyQualifiedName()] = true
+	defer delete(parents, msg.GetFullyQualifiedName())
+
+	schema.Properties = map[string]*openapi3.SchemaRef{}
+	for _, field := range msg.GetFields() {
+		var fieldSchema *openapi3.Schema
+		switch {
+		case field.IsMap():
+			fieldSchema = openapi3.NewObjectSchema()
+		case field.IsRepeated():
+			fieldSchema = openapi3.NewArraySchema()
+			fieldSchema.Items = openapi3.NewSchemaRef("", fieldTypeSchema(field, parents))
+		default:
+			fieldSchema = fieldTypeSchema(field, parents)
+		}
+		fieldSchema.Description = comment(field.GetSourceInfo())
+		schema.Properties[field.GetName()] = openapi3.NewSchemaRef("", fieldSchema)
+	}
+	return schema
+}
+
+// fieldTypeSchema returns the schema of a single value of a field
+func fieldTypeSchema(field *desc.FieldDescriptor, parents map[string]bool) *openapi3.Schema {
+	switch field.GetType() {
+	case dpb.FieldDescriptorProto_TYPE_STRING, dpb.FieldDescriptorProto_TYPE_ENUM:
+		return openapi3.NewStringSchema()
+	case dpb.FieldDescriptorProto_TYPE_BYTES:
+		return openapi3.NewBytesSchema()
+	case dpb.FieldDescriptorProto_TYPE_BOOL:
+		return openapi3.NewBoolSchema()
+	case dpb.FieldDescriptorProto_TYPE_INT32, dpb.FieldDescriptorProto_TYPE_SINT32, dpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return &openapi3.Schema{Type: "number", Format: "int32"}
+	case dpb.FieldDescriptorProto_TYPE_UINT32, dpb.FieldDescriptorProto_TYPE_FIXED32:
+		return &openapi3.Schema{Type: "number", Format: "uint32"}
+	case dpb.FieldDescriptorProto_TYPE_INT64, dpb.FieldDescriptorProto_TYPE_SINT64, dpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return &openapi3.Schema{Type: "number", Format: "int64"}
+	case dpb.FieldDescriptorProto_TYPE_UINT64, dpb.FieldDescriptorProto_TYPE_FIXED64:
+		return &openapi3.Schema{Type: "number", Format: "uint64"}
+	case dpb.FieldDescriptorProto_TYPE_FLOAT:
+		return &openapi3.Schema{Type: "number", Format: "float"}
+	case dpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return &openapi3.Schema{Type: "number", Format: "double"}
+	case dpb.FieldDescriptorProto_TYPE_MESSAGE, dpb.FieldDescriptorProto_TYPE_GROUP:
+		if field.GetMessageType().GetFile().GetPackage() == "google.protobuf" {
+			return wellKnownSchema(wellKnownTypeOf(field))
+		}
+		return messageSchema(field.GetMessageType(), parents)
+	}
+	return &openapi3.Schema{}
+}
+
+// wellKnownSchema returns the schema of the JSON encoding of a well-known
+// type, see wellKnownTypes
+func wellKnownSchema(wkt string) *openapi3.Schema {
+	switch wkt {
+	case "TIMESTAMP":
+		return openapi3.NewDateTimeSchema()
+	case "DURATION", "FIELDMASK", "STRINGVALUE":
+		return openapi3.NewStringSchema()
+	case "BYTESVALUE":
+		return openapi3.NewBytesSchema()
+	case "BOOLVALUE":
+		return openapi3.NewBoolSchema()
+	case "INT32VALUE":
+		return &openapi3.Schema{Type: "number", Format: "int32"}
+	case "UINT32VALUE":
+		return &openapi3.Schema{Type: "number", Format: "uint32"}
+	case "INT64VALUE":
+		return &openapi3.Schema{Type: "number", Format: "int64"}
+	case "UINT64VALUE":
+		return &openapi3.Schema{Type: "number", Format: "uint64"}
+	case "FLOATVALUE":
+		return &openapi3.Schema{Type: "number", Format: "float"}
+	case "DOUBLEVALUE":
+		return &openapi3.Schema{Type: "number", Format: "double"}
+	case "LISTVALUE":
+		return openapi3.NewArraySchema()
+	case "VALUE":
+		// any JSON value
+		return &openapi3.Schema{}
+	}
+	return openapi3.NewObjectSchema()
+}
+
+// comment returns the leading comment of a proto element
+func comment(info *dpb.SourceCodeInfo_Location) string {
+	if info == nil {
+		return ""
+	}
+	lines := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(info.GetLeadingComments()), "\n") {
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,165 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+)
+
+// ProtoCache holds the parsed protos of the services of a run, so a proto
+// is parsed once however many fields the generators and examples look up.
+// It can be shared by the runs of several targets as long as the protos
+// don't change in between. It's safe for concurrent use.
+type ProtoCache struct {
+	mu     sync.Mutex
+	protos map[string]*cachedProto
+}
+
+type cachedProto struct {
+	once  sync.Once
+	files *protoFiles
+	err   error
+}
+
+// NewProtoCache returns an empty proto cache
+func NewProtoCache() *ProtoCache {
+	return &ProtoCache{protos: map[string]*cachedProto{}}
+}
+
+// get returns the parsed proto of a service, parsing it on first use. The
+// protos of concurrent callers asking for the same service are parsed once.
+func (c *ProtoCache) get(service Service) (*protoFiles, error) {
+	key := service.Name + "\x00" + service.Proto
+	c.mu.Lock()
+	p, ok := c.protos[key]
+	if !ok {
+		p = &cachedProto{}
+		c.protos[key] = p
+	}
+	c.mu.Unlock()
+
+	p.once.Do(func() {
+		p.files, p.err = parseProtoFiles(service.Proto, service.ProtoPaths)
+	})
+	return p.files, p.err
+}
+
+// protoFiles is the parsed proto of a service along with the files it
+// imports, with their messages indexed by name
+type protoFiles struct {
+	file     *desc.FileDescriptor
+	messages map[string]*desc.MessageDescriptor
+}
+
+// parseProto parses the proto file of a service, through the proto cache of
+// the run when the service has one
+func parseProto(service Service) (*protoFiles, error) {
+	if service.protos == nil {
+		return parseProtoFiles(service.Proto, service.ProtoPaths)
+	}
+	return service.protos.get(service)
+}
+
+// parseProtoFiles parses a proto, its imports are looked up in its folder,
+// then in importPaths and finally in the well-known protos
+func parseProtoFiles(path string, importPaths []string) (*protoFiles, error) {
+	fd, err := parseProtoFile(path, importPaths)
+	if err != nil {
+		return nil, err
+	}
+	pf := &protoFiles{
+		file:     fd,
+		messages: map[string]*desc.MessageDescriptor{},
+	}
+	if err := pf.index(fd, map[string]bool{}); err != nil {
+		return nil, fmt.Errorf("failed to index file %v: %v", path, err)
+	}
+	return pf, nil
+}
+
+func parseProtoFile(path string, importPaths []string) (*desc.FileDescriptor, error) {
+	p := protoparse.Parser{
+		ImportPaths:           append([]string{filepath.Dir(path)}, importPaths...),
+		IncludeSourceCodeInfo: true,
+	}
+	fdesc, err := p.ParseFiles(filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %v: %v", path, err)
+	}
+	return fdesc[0], nil
+}
+
+// index adds the messages of a file and of the files it imports,
+// by fully qualified name and by flat name. The names of the service proto
+// take precedence over the ones of its imports. Messages of a package
+// sharing a flat name, e.g. Outer.Inner and OuterInner, would be generated
+// as the same type so they're an error.
+func (pf *protoFiles) index(fd *desc.FileDescriptor, seen map[string]bool) error {
+	if seen[fd.GetName()] {
+		return nil
+	}
+	seen[fd.GetName()] = true
+
+	var addMessage func(m *desc.MessageDescriptor) error
+	addMessage = func(m *desc.MessageDescriptor) error {
+		if m.IsMapEntry() {
+			return nil
+		}
+		flat := flatName(m)
+		if other, ok := pf.messages[flat]; ok && other != m && other.GetFile().GetPackage() == m.GetFile().GetPackage() {
+			return fmt.Errorf("messages %v and %v are both named %v", other.GetFullyQualifiedName(), m.GetFullyQualifiedName(), flat)
+		}
+		for _, name := range []string{m.GetFullyQualifiedName(), flat} {
+			if _, ok := pf.messages[name]; !ok {
+				pf.messages[name] = m
+			}
+		}
+		for _, nested := range m.GetNestedMessageTypes() {
+			if err := addMessage(nested); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// the well-known types are mapped to types of each language instead
+	if fd.GetPackage() != "google.protobuf" {
+		for _, m := range fd.GetMessageTypes() {
+			if err := addMessage(m); err != nil {
+				return err
+			}
+		}
+	}
+	for _, dep := range fd.GetDependencies() {
+		if err := pf.index(dep, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// message looks up a message by the name of its schema: its flat name, its
+// fully qualified name or its name in the package of the service proto
+func (pf *protoFiles) message(name string) *desc.MessageDescriptor {
+	if m, ok := pf.messages[name]; ok {
+		return m
+	}
+	if pkg := pf.file.GetPackage(); pkg != "" {
+		return pf.messages[pkg+"."+name]
+	}
+	return nil
+}
+
+// flatName is the name a message or enum is generated with, the name of a
+// nested type is prefixed with the names of its parents, e.g. the type of
+// message Inner nested in message Outer is OuterInner
+func flatName(d desc.Descriptor) string {
+	name := d.GetFullyQualifiedName()
+	if pkg := d.GetFile().GetPackage(); pkg != "" {
+		name = strings.TrimPrefix(name, pkg+".")
+	}
+	return strings.Replace(name, ".", "", -1)
+}
@@ -0,0 +1,91 @@
+package gen
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// BenchmarkSchemaToType renders the Go and TypeScript types of every schema
+// of the notes service, as a run does, parsing its proto for every lookup
+// or once per run through a new proto cache
+func BenchmarkSchemaToType(b *testing.B) {
+	spec, err := readSpec("testdata/notes/api-notes.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bench := range []struct {
+		name   string
+		protos func() *ProtoCache
+	}{
+		{"uncached", func() *ProtoCache { return nil }},
+		{"cached", NewProtoCache},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			gog, tsg := &goG{}, &tsG{}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				service := Service{
+					Name:   "notes",
+					Spec:   spec,
+					Proto:  "testdata/notes/proto/notes.proto",
+					protos: bench.protos(),
+				}
+				for typeName := range spec.Components.Schemas {
+					if _, err := gog.schemaToType(service, typeName, spec.Components.Schemas); err != nil {
+						b.Fatal(err)
+					}
+					if _, err := tsg.schemaToType(service, typeName, spec.Components.Schemas); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseProtoFilesFlatNames(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		proto string
+		// part of the error, empty if there's none
+		err string
+	}{
+		{
+			name:  "nested",
+			proto: "message Outer { message Inner {} }\nmessage Other { message Inner {} }",
+		},
+		{
+			name:  "nested and top-level",
+			proto: "message Outer { message Inner {} }\nmessage OuterInner {}",
+			err:   "messages svc.Outer.Inner and svc.OuterInner are both named OuterInner",
+		},
+		{
+			name:  "deeply nested",
+			proto: "message A { message BC {} }\nmessage AB { message C {} }",
+			err:   "messages svc.A.BC and svc.AB.C are both named ABC",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "svc.proto")
+			proto := "syntax = \"proto3\";\npackage svc;\n" + tt.proto + "\n"
+			if err := ioutil.WriteFile(path, []byte(proto), 0644); err != nil {
+				t.Fatal(err)
+			}
+			pf, err := parseProtoFiles(path, nil)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if m := pf.message("OtherInner"); m == nil || m.GetFullyQualifiedName() != "svc.Other.Inner" {
+					t.Errorf("got %v for OtherInner", m)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package gen
+
+import "path/filepath"
+
+// Target is a language or tool the generator can produce clients and/or
+// examples for
+type Target struct {
+	Name        string
+	Description string
+	// folder under the clients output directory the clients are written
+	// to, empty for targets that only produce examples
+	ClientDir string
+	// folder under the client folder holding the code of each service
+	ServicesDir string
+	// folder under the examples output directory the examples are written to
+	ExamplesDir string
+	// files in the generated folders produced by other tools, e.g. build_runner
+	Ignore []string
+	// NewGenerator returns the generator of the target writing to out
+	NewGenerator func(out Output, cfg Config) Generator
+}
+
+// Targets are the targets the generator supports
+var Targets = []Target{
+	{
+		Name:         "go",
+		Description:  "Go clients and examples",
+		ClientDir:    "go",
+		ExamplesDir:  "go",
+		NewGenerator: func(out Output, cfg Config) Generator { return &goG{out: out, cfg: cfg} },
+	},
+	{
+		Name:         "ts",
+		Description:  "TypeScript clients and javascript examples",
+		ClientDir:    "ts",
+		ServicesDir:  "src",
+		ExamplesDir:  "js",
+		NewGenerator: func(out Output, cfg Config) Generator { return &tsG{out: out, cfg: cfg} },
+	},
+	{
+		Name:         "dart",
+		Description:  "Dart clients and examples",
+		ClientDir:    "dart",
+		ServicesDir:  filepath.Join("lib", "src"),
+		ExamplesDir:  "dart",
+		Ignore:       []string{"*.freezed.dart", "*.g.dart"},
+		NewGenerator: func(out Output, cfg Config) Generator { return &dartG{out: out, cfg: cfg} },
+	},
+	{
+		Name:         "shell",
+		Description:  "curl examples",
+		ExamplesDir:  "curl",
+		NewGenerator: func(out Output, cfg Config) Generator { return &shellG{out: out, cfg: cfg} },
+	},
+	{
+		Name:         "cli",
+		Description:  "m3o cli examples",
+		ExamplesDir:  "cli",
+		NewGenerator: func(out Output, cfg Config) Generator { return &cliG{out: out, cfg: cfg} },
+	},
+}
+
+// FindTarget returns the target with the given name
+func FindTarget(name string) (Target, bool) {
+	for _, t := range Targets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Target{}, false
+}
+
+// ServiceFolders returns the folders holding only files generated for the
+// given service
+func (t Target) ServiceFolders(clientPath, examplesPath, serviceName string) []string {
+	folders := []string{filepath.Join(examplesPath, t.ExamplesDir, serviceName)}
+	if clientPath != "" {
+		folders = append(folders, filepath.Join(clientPath, t.ServicesDir, serviceName))
+	}
+	return folders
+}
+
+// Ignored reports whether a file in a generated folder comes from another tool
+func (t Target) Ignored(path string) bool {
+	for _, pattern := range t.Ignore {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+	}
+	return false
+}
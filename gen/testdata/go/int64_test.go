@@ -0,0 +1,57 @@
+package ledger_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"go.m3o.com/ledger"
+)
+
+// TestInt64JSON checks the 64 bit integers of lists and maps are encoded as
+// strings, with string, integer and bool keys, and decoded back
+func TestInt64JSON(t *testing.T) {
+	rsp := ledger.ReadResponse{
+		Entries:    ledger.Int64s{math.MinInt64, 0, math.MaxInt64},
+		Ids:        ledger.Uint64s{math.MaxUint64},
+		Balances:   ledger.StringInt64Map{"a": math.MaxInt64, "b": -1},
+		ByTime:     ledger.Int64Uint64Map{math.MinInt64: math.MaxUint64},
+		ByDay:      ledger.Uint32Int64Map{math.MaxUint32: math.MinInt64},
+		BySettled:  ledger.BoolInt64Map{true: math.MaxInt64, false: -2},
+		ByRefunded: ledger.BoolUint64Map{true: math.MaxUint64},
+	}
+	b, err := json.Marshal(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"entries":["-9223372036854775808","0","9223372036854775807"],` +
+		`"ids":["18446744073709551615"],` +
+		`"balances":{"a":"9223372036854775807","b":"-1"},` +
+		`"by_time":{"-9223372036854775808":"18446744073709551615"},` +
+		`"by_day":{"4294967295":"-9223372036854775808"},` +
+		`"by_settled":{"false":"-2","true":"9223372036854775807"},` +
+		`"by_refunded":{"true":"18446744073709551615"}}`
+	if string(b) != want {
+		t.Fatalf("got %s, want %s", b, want)
+	}
+	var got ledger.ReadResponse
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rsp) {
+		t.Fatalf("got %+v, want %+v", got, rsp)
+	}
+
+	// numbers are accepted too
+	got = ledger.ReadResponse{}
+	if err := json.Unmarshal([]byte(`{"entries":[1],"by_settled":{"true":2}}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Entries[0] != 1 || got.BySettled[true] != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	if err := json.Unmarshal([]byte(`{"by_settled":{"yes":"1"}}`), &got); err == nil {
+		t.Fatal("expected an error for a key that isn't a bool")
+	}
+}
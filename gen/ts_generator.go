@@ -0,0 +1,553 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/stoewer/go-strcase"
+)
+
+type tsG struct {
+	Generator
+	out Output
+	cfg Config
+}
+
+func (n *tsG) ServiceClient(serviceName, tsPath string, service Service) error {
+	clientFile := filepath.Join(tsPath, "src", serviceName, "index.ts")
+	b, err := render("ts"+serviceName, tsServiceTemplate, map[string]interface{}{
+		"service": service,
+		"config":  n.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", clientFile, err)
+	}
+	err = n.out.WriteFile(clientFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (n *tsG) TopReadme(serviceName, examplesPath string, service Service) error {
+	// node client service readmes
+	readme := filepath.Join(examplesPath, "js", serviceName, "README.md")
+	b, err := render("tsTopReadme"+serviceName, tsReadmeTopTemplate, map[string]interface{}{
+		"service": service,
+		"config":  n.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", readme, err)
+	}
+	err = n.out.WriteFile(readme, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+func (n *tsG) ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title string, service Service, example Example) error {
+	data := map[string]interface{}{
+		"service":  service,
+		"config":   n.cfg,
+		"example":  example,
+		"endpoint": endpoint,
+		"funcName": strcase.UpperCamelCase(title),
+	}
+
+	// node example
+	tsExampleFile := filepath.Join(examplesPath, "js", serviceName, endpoint, title+".js")
+	b, err := render("ts"+serviceName+endpoint, tsExampleTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", tsExampleFile, err)
+	}
+	err = n.out.WriteFile(tsExampleFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	if example.RunCheck && example.Idempotent {
+		err = n.out.WriteFile(filepath.Join(examplesPath, "js", serviceName, endpoint, ".run"+strcase.UpperCamelCase(title)), []byte{})
+		if err != nil {
+			return fmt.Errorf("failed to write run file: %v", err)
+		}
+	}
+
+	// per endpoint readme examples
+	tsReadmeAppend := filepath.Join(examplesPath, "js", serviceName, "README.md")
+	b, err = render("tsBottomReadme"+serviceName+endpoint, tsReadmeBottomTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", tsReadmeAppend, err)
+	}
+	err = n.out.AppendFile(tsReadmeAppend, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	// cmd := exec.Command("prettier", "-w", title+".js")
+	// cmd.Dir = filepath.Join(examplesPath, "js", serviceName, endpoint)
+	// outp, err := cmd.CombinedOutput()
+	// if err != nil {
+	// 	return fmt.Errorf("problem with '%v' example '%v': %v", serviceName, endpoint, err)
+	// }
+	// fmt.Println(outp)
+	return nil
+}
+
+func (n *tsG) IndexFile(tsPath string, services []Service) error {
+	indexFile := filepath.Join(tsPath, "index.ts")
+	b, err := render("tsclient", tsIndexTemplate, map[string]interface{}{
+		"services": services,
+		"config":   n.cfg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %v: %v", indexFile, err)
+	}
+	err = n.out.WriteFile(indexFile, b)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	return nil
+}
+
+// schemaToType returns the fields of a type, the fields of its oneofs are
+// declared by tsOneofs
+func (n *tsG) schemaToType(service Service, typeName string, schemas map[string]*openapi3.SchemaRef) (string, error) {
+	return n.schemaToFields(service, typeName, schemas, func(p string) bool {
+		return oneofOf(service, typeName, p) == nil
+	})
+}
+
+// schemaToFields returns the fields of a type for which include returns true
+func (n *tsG) schemaToFields(service Service, typeName string, schemas map[string]*openapi3.SchemaRef, include func(p string) bool) (string, error) {
+	var normalType = `{{ .parameter }}?: {{ .type }};`
+	var arrayType = `{{ .parameter }}?: {{ .type }}[];`
+	var mapType = ` {{ .parameter }}?: { [key:{{ .type1 }}]: {{ .type2 }} };`
+	var anyType = `{{ .parameter }}?: any;`
+	var stringType = "string"
+	var number = "number"
+	var int64Type = tsInt64Type(n.cfg)
+	var boolType = "boolean"
+	// var typePrefix = "*"
+
+	runTemplate := func(tmpName, temp string, payload map[string]interface{}) string {
+		t, err := template.New(tmpName).Parse(temp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to parse %s - err: %v\n", temp, err)
+			return ""
+		}
+		var tb bytes.Buffer
+		err = t.Execute(&tb, payload)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "faild to apply parsed template %s to payload %v - err: %v\n", temp, payload, err)
+			return ""
+		}
+
+		return tb.String()
+	}
+
+	typesMapper := func(t string) string {
+		switch t {
+		case "STRING":
+			return stringType
+		case "INT32", "UINT32", "SINT32", "FIXED32", "SFIXED32", "FLOAT", "DOUBLE":
+			return number
+		case "INT64", "UINT64", "SINT64", "FIXED64", "SFIXED64":
+			return int64Type
+		case "BOOL":
+			return boolType
+		default:
+			if wkt, ok := tsWellKnownTypes[t]; ok {
+				return wkt
+			}
+			return t
+		}
+	}
+
+	output := []string{}
+	protoMessage := schemas[typeName]
+	if protoMessage == nil || protoMessage.Value == nil {
+		return "", fmt.Errorf("can't find %v in the spec", typeName)
+	}
+
+	// return an empty string if there is no properties for the typeName
+	if len(protoMessage.Value.Properties) == 0 {
+		return "", nil
+	}
+
+	for _, p := range propertyNames(service, typeName, protoMessage.Value.Properties) {
+		if !include(p) {
+			continue
+		}
+		meta := protoMessage.Value.Properties[p]
+		comments := ""
+		o := ""
+
+		if meta.Value.Description != "" {
+			for _, commentLine := range strings.Split(meta.Value.Description, "\n") {
+				comments += "// " + strings.TrimSpace(commentLine) + "\n"
+			}
+		}
+
+		// well-known types are mapped to native types, whatever the spec says
+		if wkt, repeated, isMap := wellKnownType(service, typeName, p); wkt != "" && !isMap {
+			payload := map[string]interface{}{
+				"type":      tsWellKnownTypes[wkt],
+				"parameter": p,
+			}
+			if wkt == "INT64VALUE" || wkt == "UINT64VALUE" {
+				payload["type"] = int64Type
+			}
+			if repeated {
+				o = runTemplate("array", arrayType, payload)
+			} else {
+				if _, ok := tsWrapperTypes[wkt]; ok {
+					payload["type"] = payload["type"].(string) + " | null"
+				}
+				o = runTemplate("normal", normalType, payload)
+			}
+			output = append(output, comments+o)
+			continue
+		}
+		if enum, repeated := enumField(service, typeName, p); enum != "" && !repeated {
+			o = runTemplate("normal", normalType, map[string]interface{}{
+				"type":      enum,
+				"parameter": p,
+			})
+			output = append(output, comments+o)
+			continue
+		}
+
+		switch meta.Value.Type {
+		case "string":
+			payload := map[string]interface{}{
+				"type":      stringType,
+				"parameter": p,
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "boolean":
+			payload := map[string]interface{}{
+				"type":      boolType,
+				"parameter": p,
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "number":
+			payload := map[string]interface{}{
+				"type":      number,
+				"parameter": p,
+			}
+			if meta.Value.Format == "int64" || meta.Value.Format == "uint64" {
+				payload["type"] = int64Type
+			}
+			o = runTemplate("normal", normalType, payload)
+		case "array":
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			payload := map[string]interface{}{
+				"type":      typesMapper(types[0]),
+				"parameter": p,
+			}
+			o = runTemplate("array", arrayType, payload)
+		case "object":
+			types, err := detectType2(service, typeName, p)
+			if err != nil {
+				return "", err
+			}
+			if len(types) == 1 {
+				// a Message Type
+				payload := map[string]interface{}{
+					"type":      typesMapper(types[0]),
+					"parameter": p,
+				}
+				o = runTemplate("normal", normalType, payload)
+			} else {
+				// a Map object, keys can't be typed as bigint
+				payload := map[string]interface{}{
+					"type1":     typesMapper(types[0]),
+					"type2":     typesMapper(types[1]),
+					"parameter": p,
+				}
+				switch types[0] {
+				case "INT64", "UINT64", "SINT64", "FIXED64", "SFIXED64":
+					payload["type1"] = number
+				}
+				o = runTemplate("map", mapType, payload)
+			}
+		default:
+			payload := map[string]interface{}{
+				"parameter": p,
+			}
+			o = runTemplate("any", anyType, payload)
+		}
+
+		output = append(output, comments+o)
+	}
+
+	return strings.Join(output, "\n"), nil
+}
+
+func publishToNpm(tsPath string, tsFileList []string) error {
+	// login to NPM
+	f, err := os.OpenFile(filepath.Join(tsPath, ".npmrc"), os.O_TRUNC|os.O_WRONLY|os.O_CREATE, FILE_EXECUTE_PERMISSION)
+	if err != nil {
+		return fmt.Errorf("failed to open npmrc: %v", err)
+	}
+
+	defer f.Close()
+	if len(os.Getenv("NPM_TOKEN")) == 0 {
+		return fmt.Errorf("no NPM_TOKEN env found")
+	}
+	if _, err = f.WriteString("//registry.npmjs.org/:_authToken=" + os.Getenv("NPM_TOKEN")); err != nil {
+		return fmt.Errorf("failed to open npmrc: %v", err)
+	}
+
+	// get latest version from github
+	getVersions := exec.Command("npm", "show", "m3o", "--time", "--json")
+	getVersions.Dir = tsPath
+
+	outp, err := getVersions.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to get versions of NPM package: %v", string(outp))
+	}
+	type npmVers struct {
+		Versions []string `json:"versions"`
+	}
+
+	beta := os.Getenv("IS_BETA") != ""
+	if beta {
+		fmt.Println("creating beta version")
+	} else {
+		fmt.Println("creating live version")
+	}
+
+	npmOutput := &npmVers{}
+	var latest *semver.Version
+	if len(outp) > 0 {
+		err = json.Unmarshal(outp, npmOutput)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal versions: %v", string(outp))
+		}
+	}
+	fmt.Println("npm output version: ", npmOutput.Versions)
+
+	for _, version := range npmOutput.Versions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			return fmt.Errorf("failed to parse semver: %v", err)
+		}
+		if latest == nil {
+			latest = v
+		}
+		if v.GreaterThan(latest) {
+			latest = v
+		}
+
+	}
+
+	if latest == nil {
+		return fmt.Errorf("found no semver version")
+	}
+
+	var newV semver.Version
+	if beta {
+		// bump a beta version
+		if strings.Contains(latest.String(), "beta") {
+			newV, err = incBeta(*latest)
+			if err != nil {
+				return err
+			}
+		} else {
+			// make beta out of latest non beta version
+			v, _ := semver.NewVersion(latest.IncPatch().String() + "-beta1")
+			newV = *v
+		}
+	} else {
+		newV = latest.IncPatch()
+	}
+
+	// bump package to latest version
+	fmt.Println("Bumping to ", newV.String())
+	repl := exec.Command("sed", "-i", "-e", "s/1.0.1/"+newV.String()+"/g", "package.json")
+	repl.Dir = tsPath
+	outp, err = repl.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to make docs: %v", string(outp))
+	}
+
+	// apppend exports to to package.json
+	pak, err := ioutil.ReadFile(filepath.Join(tsPath, "package.json"))
+	if err != nil {
+		return err
+	}
+	m := map[string]interface{}{}
+	err = json.Unmarshal(pak, &m)
+	if err != nil {
+		return err
+	}
+	m["files"] = tsFileList
+	pakJS, err := json.MarshalIndent(m, "", " ")
+	if err != nil {
+		return err
+	}
+	f, err = os.OpenFile(filepath.Join(tsPath, "package.json"), os.O_TRUNC|os.O_WRONLY|os.O_CREATE, FILE_EXECUTE_PERMISSION)
+	if err != nil {
+		return fmt.Errorf("failed to open package.json: %v", err)
+	}
+	_, err = f.Write(pakJS)
+	if err != nil {
+		return fmt.Errorf("failed to write to package.json: %v", err)
+	}
+	return nil
+}
+
+// tsWellKnownTypes are the TypeScript types of single values of the
+// well-known types, durations are in milliseconds like the ones of
+// setTimeout and Date
+var tsWellKnownTypes = map[string]string{
+	"JSON":        "{ [key: string]: any }",
+	"ANY":         "{ [key: string]: any }",
+	"EMPTY":       "{ [key: string]: any }",
+	"VALUE":       "any",
+	"LISTVALUE":   "any[]",
+	"TIMESTAMP":   "Date",
+	"DURATION":    "number",
+	"FIELDMASK":   "string[]",
+	"DOUBLEVALUE": "number",
+	"FLOATVALUE":  "number",
+	"INT64VALUE":  "number",
+	"UINT64VALUE": "number",
+	"INT32VALUE":  "number",
+	"UINT32VALUE": "number",
+	"BOOLVALUE":   "boolean",
+	"STRINGVALUE": "string",
+	// base64 encoded
+	"BYTESVALUE": "string",
+}
+
+// tsWrapperTypes are the wrappers, their fields can be set to null
+var tsWrapperTypes = map[string]bool{
+	"DOUBLEVALUE": true,
+	"FLOATVALUE":  true,
+	"INT64VALUE":  true,
+	"UINT64VALUE": true,
+	"INT32VALUE":  true,
+	"UINT32VALUE": true,
+	"BOOLVALUE":   true,
+	"STRINGVALUE": true,
+	"BYTESVALUE":  true,
+}
+
+// tsConversionKinds are the well-known types whose JSON encoding differs
+// from their TypeScript type, by the kind of conversion tsConvertTemplate
+// applies to them
+var tsConversionKinds = map[string]string{
+	"TIMESTAMP": "timestamp",
+	"DURATION":  "duration",
+	"FIELDMASK": "fieldmask",
+}
+
+// tsInt64Type returns the TypeScript type of the 64 bit integers, see
+// Config.Int64
+func tsInt64Type(cfg Config) string {
+	if cfg.Int64 == "" {
+		return "number"
+	}
+	return cfg.Int64
+}
+
+// tsConversions returns the code converting the requests and responses of
+// a service to and from their JSON encoding, empty when the types of the
+// service match their JSON encoding
+func tsConversions(service Service) (string, error) {
+	int64Type := tsInt64Type(service.config)
+	conversions := fieldConversions(service, func(field *desc.FieldDescriptor) string {
+		// numbers are left as is, like before the option existed
+		if int64Type != "number" && int64Kind(field) != "" {
+			return "int64"
+		}
+		return tsConversionKinds[wellKnownTypeOf(field)]
+	})
+	if len(conversions) == 0 {
+		return "", nil
+	}
+	b, err := json.MarshalIndent(conversions, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	ret, err := render("tsConvert"+service.Name, tsConvertTemplate, map[string]interface{}{
+		"conversions": string(b),
+		"int64":       int64Type,
+	})
+	return string(ret), err
+}
+
+// tsEnums returns the declarations of the enums used by a service, unions
+// of the names of their values
+func tsEnums(service Service) (string, error) {
+	b, err := render("tsEnums"+service.Name, tsEnumTemplate, map[string]interface{}{
+		"enums": serviceEnums(service),
+	})
+	return string(b), err
+}
+
+// tsOneofs returns the types of the oneofs of a type, unions of objects
+// setting one of their fields, and the intersection with them completing the
+// declaration of the type. It's empty when the type has no oneofs.
+func tsOneofs(service Service, typeName string) (string, error) {
+	oneofs := messageOneofs(service, typeName)
+	if len(oneofs) == 0 {
+		return "", nil
+	}
+	n := &tsG{cfg: service.config}
+	schemas := service.Spec.Components.Schemas
+	types := []map[string]interface{}{}
+	for _, oo := range oneofs {
+		variants := []string{}
+		for _, field := range oo.Fields {
+			def, err := n.schemaToFields(service, typeName, schemas, func(p string) bool {
+				return p == field
+			})
+			if err != nil {
+				return "", err
+			}
+			// the other fields can't be set
+			others := []string{}
+			for _, other := range oo.Fields {
+				if other != field {
+					others = append(others, other+"?: never;")
+				}
+			}
+			// the variants are on a line, with the comments as block comments
+			parts := []string{}
+			for _, line := range strings.Split(def, "\n") {
+				if strings.HasPrefix(line, "//") {
+					line = "/* " + strings.TrimSpace(strings.TrimPrefix(line, "//")) + " */"
+				}
+				parts = append(parts, strings.TrimSpace(line))
+			}
+			variants = append(variants, strings.Join(append(parts, others...), " "))
+		}
+		types = append(types, map[string]interface{}{
+			"name":     strings.Title(typeName) + strcase.UpperCamelCase(oo.Name),
+			"comment":  oo.Comment,
+			"fields":   oo.names(func(f string) string { return f }),
+			"variants": variants,
+		})
+	}
+	b, err := render("tsOneofs"+service.Name+typeName, tsOneofTemplate, map[string]interface{}{
+		"types": types,
+	})
+	return string(b), err
+}
@@ -0,0 +1,155 @@
+package gen
+
+const tsIndexTemplate = `{{ range $service := .services }}import * as {{ $service.ImportName }} from './{{ $service.Name }}';
+{{ end }}
+export class Client {
+	constructor(token: string) {
+		{{ range $service := .services }}
+		this.{{ $service.Name}} = new {{ $service.ImportName }}.{{ title $service.Name}}Service(token);{{end}}
+	}
+{{ range $service := .services }}
+	{{ $service.Name}}: {{ $service.ImportName }}.{{ title $service.Name}}Service;{{end}}
+}
+export default (token = process.env.{{ .config.TokenEnv }} as string) => {
+	return {
+		{{ range $service := .services }}
+		{{ $service.Name}}: new {{ $service.ImportName }}.{{ title $service.Name}}Service(token),{{end}}
+	}
+}
+`
+
+const tsServiceTemplate = `import * as m3o from '@m3o/m3o-node';
+
+{{ $service := .service }}{{ $conversions := tsConversions $service }}
+export class {{ title $service.Name }}Service{
+	private client: m3o.Client;
+
+	constructor(token: string) {
+		this.client = new m3o.Client({token: token})
+	}
+	{{ range $key, $req := $service.Spec.Components.RequestBodies }}{{ $reqType := requestType $key }}{{ $endpointName := requestTypeToEndpointName $key}}{{ if endpointComment $endpointName $service.Spec.Components.Schemas }}{{ endpointComment $endpointName $service.Spec.Components.Schemas }}{{ end }}{{ untitle $endpointName}}(request: {{ requestType $key }}): {{ if isStream $service.Spec $service.Name $reqType }}Promise<m3o.Stream<{{ $reqType }}, {{ requestTypeToResponseType $key }}>>{{ end }}{{ if isNotStream $service.Spec $service.Name $reqType }}Promise<{{ requestTypeToResponseType $key }}>{{ end }} {
+		{{ if $conversions }}{{ if isStream $service.Spec $service.Name $reqType }}return this.client.stream("{{ $service.Name }}", "{{ requestTypeToEndpointPath $key}}", convert("{{ $reqType }}", request, true)).then(stream => {
+			const onMessage = stream.onMessage.bind(stream);
+			stream.onMessage = (fn: (msg: {{ requestTypeToResponseType $key }}) => void) => onMessage((msg: any) => fn(convert("{{ requestTypeToResponseType $key }}", msg, false)));
+			return stream;
+		});{{ end }}{{ if isNotStream $service.Spec $service.Name $reqType }}return this.client.call("{{ $service.Name }}", "{{ requestTypeToEndpointPath $key}}", convert("{{ $reqType }}", request, true)).then(rsp => convert("{{ requestTypeToResponseType $key }}", rsp, false)) as Promise<{{ requestTypeToResponseType $key }}>;{{ end }}{{ else }}{{ if isStream $service.Spec $service.Name $reqType }}return this.client.stream("{{ $service.Name }}", "{{ requestTypeToEndpointPath $key}}", request);{{ end }}{{ if isNotStream $service.Spec $service.Name $reqType }}return this.client.call("{{ $service.Name }}", "{{ requestTypeToEndpointPath $key}}", request) as Promise<{{ requestTypeToResponseType $key }}>;{{ end }}{{ end }}
+	};
+	{{ end }}
+}
+
+{{ range $typeName, $schema := $service.Spec.Components.Schemas }}{{ $oneofs := tsOneofs $service $typeName }}
+{{ if $oneofs }}export type {{ title $typeName }} = {{ "{" }}
+{{ recursiveTypeDefinitionTs $service $typeName $service.Spec.Components.Schemas }}{{ "}" }}{{ $oneofs }}
+{{ else }}export interface {{ title $typeName }}{{ "{" }}
+{{ recursiveTypeDefinitionTs $service $typeName $service.Spec.Components.Schemas }}{{ "}" }}
+{{ end }}{{end}}
+{{ tsEnums $service }}{{ $conversions }}`
+
+// tsOneofTemplate renders the oneofs of a type, see tsOneofs
+const tsOneofTemplate = `{{ range $type := .types }} & {{ $type.name }}{{ end }};
+{{ range $type := .types }}
+{{ commentLines "// " $type.comment }}// only one of {{ $type.fields }} may be set
+export type {{ $type.name }} ={{ range $variant := $type.variants }}
+	| { {{ $variant }} }{{ end }};
+{{ end }}`
+
+// tsEnumTemplate renders the enums used by a service, see tsEnums
+const tsEnumTemplate = `{{ range $enum := .enums }}
+{{ commentLines "// " $enum.Comment }}export type {{ $enum.Name }} ={{ range $value := $enum.Values }}
+{{ commentLines "\t// " $value.Comment }}	| "{{ $value.Name }}"{{ end }};
+{{ end }}`
+
+// tsConvertTemplate converts the values of a service to and from their JSON
+// encoding, see tsConversions. Values already encoded, e.g. the timestamps
+// of the examples, are left as is.
+const tsConvertTemplate = `
+// the fields converted to and from their JSON encoding, by type
+const conversions: { [type: string]: { [field: string]: string } } = {{ .conversions }};
+
+function convert(kind: string, value: any, toJSON: boolean): any {
+	if (value === undefined || value === null) {
+		return value;
+	}
+	if (kind.startsWith("[]")) {
+		return (value as any[]).map(v => convert(kind.slice(2), v, toJSON));
+	}
+	if (kind.startsWith("{}")) {
+		const ret: { [key: string]: any } = {};
+		for (const key of Object.keys(value)) {
+			ret[key] = convert(kind.slice(2), value[key], toJSON);
+		}
+		return ret;
+	}
+	switch (kind) {
+	case "timestamp":
+		return toJSON ? new Date(value).toISOString() : new Date(value);
+	case "duration":
+		if (toJSON) {
+			return typeof value === "string" ? value : value / 1000 + "s";
+		}
+		return parseFloat(value) * 1000;
+	case "fieldmask":
+		if (toJSON) {
+			return typeof value === "string" ? value : value.join(",");
+		}
+		return value === "" ? [] : value.split(",");
+	case "int64":
+		// 64 bit integers are encoded as strings, numbers are accepted too
+		return toJSON ? value.toString() : {{ if eq .int64 "bigint" }}BigInt(value){{ else }}String(value){{ end }};
+	}
+	const fields = conversions[kind];
+	if (!fields) {
+		return value;
+	}
+	const ret = { ...value };
+	for (const field of Object.keys(fields)) {
+		if (field in ret) {
+			ret[field] = convert(fields[field], ret[field], toJSON);
+		}
+	}
+	return ret;
+}
+`
+
+const tsExampleTemplate = `{{ $service := .service }}const m3o = require('{{ .config.ImportPath }}')(process.env.{{ .config.TokenEnv }})
+
+async function main() {
+        let rsp = await m3o.{{ $service.Name }}.{{ .endpoint }}({{ tsExampleRequest $service.Name .endpoint $service.Spec.Components.Schemas .example.Request }})
+        {{ $reqType := requestType .endpoint }}{{ if isNotStream $service.Spec $service.Name $reqType }}console.log(rsp)
+        {{ end }}{{ if isStream $service.Spec $service.Name $reqType }}rsp.onMessage(msg => {
+                console.log(msg)
+        }){{ end}}
+}
+
+main()`
+
+const tsReadmeTopTemplate = `{{ $service := .service }}# {{ title $service.Name }}
+
+An [m3o.com](https://m3o.com) API. For example usage see [m3o.com/{{ title $service.Name }}/api](https://m3o.com/{{ title $service.Name }}/api).
+
+Endpoints:
+
+`
+
+const tsReadmeBottomTemplate = `{{ $service := .service }}## {{ title .endpoint}}
+
+{{ endpointDescription .endpoint $service.Spec.Components.Schemas }}
+
+[https://m3o.com/{{ $service.Name }}/api#{{ title .endpoint}}](https://m3o.com/{{ $service.Name }}/api#{{ title .endpoint}})
+
+` + "```" + `js
+const { {{ title $service.Name }}Service } = require('{{ .config.ImportPath }}/{{ $service.Name }}');
+
+const {{ $service.Name }}Service = new {{ title $service.Name }}Service(process.env.{{ .config.TokenEnv }})
+
+{{ if endpointComment .endpoint $service.Spec.Components.Schemas }}{{ endpointComment .endpoint $service.Spec.Components.Schemas }}{{ end }}async function {{ untitle .funcName }}() {
+	const rsp = await {{ $service.Name }}Service.{{ .endpoint }}({{ tsExampleRequest $service.Name .endpoint $service.Spec.Components.Schemas .example.Request }})
+	{{ $reqType := requestType .endpoint }}{{ if isNotStream $service.Spec $service.Name $reqType }}console.log(rsp)
+	{{ end }}{{ if isStream $service.Spec $service.Name $reqType }}rsp.onMessage(msg => {
+		console.log(msg)
+	}){{ end}}
+}
+
+{{ untitle .funcName }}()
+` + "```" + `
+`
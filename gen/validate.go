@@ -0,0 +1,230 @@
+package gen
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ExampleProblem is a mismatch between an example and the spec of its
+// service, located by the examples.json it's in and a JSON pointer
+type ExampleProblem struct {
+	Path    string
+	Pointer string
+	Message string
+}
+
+func (p ExampleProblem) String() string {
+	return fmt.Sprintf("%v#%v: %v", p.Path, p.Pointer, p.Message)
+}
+
+// ValidateExamples checks the examples of a service, read from path,
+// against its spec: every endpoint must exist and every request and
+// response must match the schema of the endpoint, without unknown fields,
+// values of the wrong type or missing required fields. The problems are
+// sorted by endpoint.
+func ValidateExamples(serviceName string, spec *openapi3.Swagger, path string, examples map[string][]Example) []ExampleProblem {
+	problems := []ExampleProblem{}
+	report := func(pointer, format string, args ...interface{}) {
+		problems = append(problems, ExampleProblem{
+			Path:    path,
+			Pointer: pointer,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	endpoints := []string{}
+	for endpoint := range examples {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	for _, endpoint := range endpoints {
+		pointer := "/" + escapePointer(endpoint)
+		if !hasEndpoint(spec, serviceName, endpoint) {
+			report(pointer, "no endpoint %v in the spec of %v", endpoint, serviceName)
+			continue
+		}
+		for i, ex := range examples[endpoint] {
+			for _, part := range []struct {
+				name   string
+				schema string
+				value  map[string]interface{}
+			}{
+				{"request", strings.Title(endpoint) + "Request", ex.Request},
+				{"response", strings.Title(endpoint) + "Response", ex.Response},
+			} {
+				schema := spec.Components.Schemas[part.schema]
+				if schema == nil || schema.Value == nil {
+					report(fmt.Sprintf("%v/%v/%v", pointer, i, part.name), "no schema %v in the spec of %v", part.schema, serviceName)
+					continue
+				}
+				if part.value == nil {
+					continue
+				}
+				validateValue(fmt.Sprintf("%v/%v/%v", pointer, i, part.name), schema.Value, part.value, report)
+			}
+		}
+	}
+	return problems
+}
+
+// hasEndpoint reports whether the spec has a path for the endpoint, matched
+// case insensitively like the generators do
+func hasEndpoint(spec *openapi3.Swagger, serviceName, endpoint string) bool {
+	// eg. "/notes/Notes/Events"
+	return findPath(spec, fmt.Sprintf("/%v/%v/%v", serviceName, strings.Title(serviceName), endpoint)) != nil
+}
+
+// reportFunc records a problem found at a JSON pointer
+type reportFunc func(pointer, format string, args ...interface{})
+
+// validateValue checks a decoded JSON value against a schema, reporting
+// every mismatch found under pointer
+func validateValue(pointer string, schema *openapi3.Schema, value interface{}, report reportFunc) {
+	// null is the zero value of any field
+	if value == nil {
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			report(pointer, "expected an object, got %v", jsonType(value))
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				report(pointer, "missing required field %v", name)
+			}
+		}
+		// objects without properties are maps or free form JSON
+		if len(schema.Properties) == 0 {
+			if schema.AdditionalProperties == nil || schema.AdditionalProperties.Value == nil {
+				return
+			}
+			for _, key := range sortedKeys(obj) {
+				validateValue(pointer+"/"+escapePointer(key), schema.AdditionalProperties.Value, obj[key], report)
+			}
+			return
+		}
+		for _, key := range sortedKeys(obj) {
+			prop, ok := schema.Properties[key]
+			if !ok || prop.Value == nil {
+				report(pointer+"/"+escapePointer(key), "unknown field %v", key)
+				continue
+			}
+			validateValue(pointer+"/"+escapePointer(key), prop.Value, obj[key], report)
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			report(pointer, "expected an array, got %v", jsonType(value))
+			return
+		}
+		if schema.Items == nil || schema.Items.Value == nil {
+			return
+		}
+		for i, item := range arr {
+			validateValue(fmt.Sprintf("%v/%v", pointer, i), schema.Items.Value, item, report)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			report(pointer, "expected a string, got %v", jsonType(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report(pointer, "expected a boolean, got %v", jsonType(value))
+		}
+	case "number", "integer":
+		validateNumber(pointer, schema, value, report)
+	}
+}
+
+// validateNumber checks a number against the format of its schema. 64 bit
+// integers can also be given as strings, as protobuf JSON encodes them.
+func validateNumber(pointer string, schema *openapi3.Schema, value interface{}, report reportFunc) {
+	var n float64
+	switch v := value.(type) {
+	case float64:
+		n = v
+	case string:
+		if schema.Format != "int64" && schema.Format != "uint64" {
+			report(pointer, "expected a number, got a string")
+			return
+		}
+		var err error
+		if schema.Format == "uint64" {
+			_, err = strconv.ParseUint(v, 10, 64)
+		} else {
+			_, err = strconv.ParseInt(v, 10, 64)
+		}
+		if err != nil {
+			report(pointer, "%q is not a valid %v", v, schema.Format)
+		}
+		return
+	default:
+		report(pointer, "expected a number, got %v", jsonType(value))
+		return
+	}
+
+	integer := schema.Type == "integer"
+	switch schema.Format {
+	case "int32", "int64", "uint32", "uint64", "sint32", "sint64", "fixed32", "fixed64", "sfixed32", "sfixed64":
+		integer = true
+	}
+	if integer && n != math.Trunc(n) {
+		report(pointer, "expected an integer, got %v", n)
+		return
+	}
+	switch schema.Format {
+	case "int32", "sint32", "sfixed32":
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			report(pointer, "%v overflows an int32", n)
+		}
+	case "uint32", "fixed32":
+		if n < 0 || n > math.MaxUint32 {
+			report(pointer, "%v overflows a uint32", n)
+		}
+	case "uint64", "fixed64":
+		if n < 0 {
+			report(pointer, "expected an unsigned integer, got %v", n)
+		}
+	}
+}
+
+// jsonType names the type of a decoded JSON value for error messages
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// escapePointer escapes a JSON pointer reference token, see RFC 6901
+func escapePointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
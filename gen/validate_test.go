@@ -0,0 +1,186 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const validateSpec = `{
+	"paths": {"/shop/Shop/Order": {}},
+	"components": {"schemas": {
+		"OrderRequest": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"paid": {"type": "boolean"},
+				"count": {"type": "number", "format": "int32"},
+				"total": {"type": "number", "format": "int64"},
+				"account": {"type": "number", "format": "uint64"},
+				"size": {"type": "number", "format": "uint32"},
+				"score": {"type": "number", "format": "double"},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"limits": {"type": "object", "additionalProperties": {"type": "number", "format": "int64"}},
+				"item": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {"name": {"type": "string"}}
+				}
+			}
+		},
+		"OrderResponse": {
+			"type": "object",
+			"properties": {"id": {"type": "string"}}
+		}
+	}}
+}`
+
+func TestValidateExamples(t *testing.T) {
+	spec := &openapi3.Swagger{}
+	if err := json.Unmarshal([]byte(validateSpec), spec); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name     string
+		endpoint string
+		request  string
+		response string
+		// the pointers and messages of the problems found
+		want [][2]string
+	}{
+		{
+			name:     "valid",
+			endpoint: "order",
+			request: `{"id": "1", "paid": true, "count": 2, "total": "9007199254740993", "account": "18446744073709551615",
+				"size": 4294967295, "score": 1.5, "tags": ["a"], "limits": {"a": "1", "b": 2}, "item": {"name": "x"}}`,
+			response: `{"id": "1"}`,
+		},
+		{
+			name:     "null values",
+			endpoint: "order",
+			request:  `{"id": null, "item": null, "tags": null}`,
+		},
+		{
+			name:     "unknown endpoint",
+			endpoint: "refund",
+			request:  `{}`,
+			want:     [][2]string{{"/refund", "no endpoint refund in the spec of shop"}},
+		},
+		{
+			name:     "unknown fields",
+			endpoint: "order",
+			request:  `{"a/b": 1, "c~d": 2}`,
+			response: `{"status": "ok"}`,
+			want: [][2]string{
+				{"/order/0/request/a~1b", "unknown field a/b"},
+				{"/order/0/request/c~0d", "unknown field c~d"},
+				{"/order/0/response/status", "unknown field status"},
+			},
+		},
+		{
+			name:     "escaped map keys",
+			endpoint: "order",
+			request:  `{"limits": {"x/y": "z"}}`,
+			want:     [][2]string{{"/order/0/request/limits/x~1y", `"z" is not a valid int64`}},
+		},
+		{
+			name:     "missing required field",
+			endpoint: "order",
+			request:  `{"item": {}}`,
+			want:     [][2]string{{"/order/0/request/item", "missing required field name"}},
+		},
+		{
+			name:     "wrong types",
+			endpoint: "order",
+			request:  `{"id": 1, "paid": "yes", "count": "2", "tags": "a", "item": [], "score": true}`,
+			want: [][2]string{
+				{"/order/0/request/count", "expected a number, got a string"},
+				{"/order/0/request/id", "expected a string, got a number"},
+				{"/order/0/request/item", "expected an object, got an array"},
+				{"/order/0/request/paid", "expected a boolean, got a string"},
+				{"/order/0/request/score", "expected a number, got a boolean"},
+				{"/order/0/request/tags", "expected an array, got a string"},
+			},
+		},
+		{
+			name:     "array items",
+			endpoint: "order",
+			request:  `{"tags": ["a", 2]}`,
+			want:     [][2]string{{"/order/0/request/tags/1", "expected a string, got a number"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ex := Example{}
+			if err := json.Unmarshal([]byte(tt.request), &ex.Request); err != nil {
+				t.Fatal(err)
+			}
+			if tt.response != "" {
+				if err := json.Unmarshal([]byte(tt.response), &ex.Response); err != nil {
+					t.Fatal(err)
+				}
+			}
+			problems := ValidateExamples("shop", spec, "examples.json", map[string][]Example{
+				tt.endpoint: {ex},
+			})
+			got := [][2]string{}
+			for _, p := range problems {
+				if p.Path != "examples.json" {
+					t.Errorf("got path %v", p.Path)
+				}
+				got = append(got, [2]string{p.Pointer, p.Message})
+			}
+			if tt.want == nil {
+				tt.want = [][2]string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNumber(t *testing.T) {
+	for _, tt := range []struct {
+		format string
+		value  interface{}
+		// the message of the problem, empty if there's none
+		want string
+	}{
+		{"int32", float64(2147483647), ""},
+		{"int32", float64(2147483648), "2.147483648e+09 overflows an int32"},
+		{"int32", float64(-2147483649), "-2.147483649e+09 overflows an int32"},
+		{"int32", 1.5, "expected an integer, got 1.5"},
+		{"int32", "1", "expected a number, got a string"},
+		{"uint32", float64(4294967295), ""},
+		{"uint32", float64(4294967296), "4.294967296e+09 overflows a uint32"},
+		{"uint32", float64(-1), "-1 overflows a uint32"},
+		{"fixed32", float64(-1), "-1 overflows a uint32"},
+		{"int64", "-9223372036854775808", ""},
+		{"int64", "9223372036854775808", `"9223372036854775808" is not a valid int64`},
+		{"int64", "1.5", `"1.5" is not a valid int64`},
+		{"int64", float64(-3), ""},
+		{"int64", 2.5, "expected an integer, got 2.5"},
+		{"uint64", "18446744073709551615", ""},
+		{"uint64", "18446744073709551616", `"18446744073709551616" is not a valid uint64`},
+		{"uint64", "-1", `"-1" is not a valid uint64`},
+		{"uint64", float64(-1), "expected an unsigned integer, got -1"},
+		{"fixed64", float64(-1), "expected an unsigned integer, got -1"},
+		{"double", 1.5, ""},
+		{"double", "1.5", "expected a number, got a string"},
+		{"float", false, "expected a number, got a boolean"},
+	} {
+		got := ""
+		validateNumber("/n", &openapi3.Schema{Type: "number", Format: tt.format}, tt.value, func(pointer, format string, args ...interface{}) {
+			if pointer != "/n" {
+				t.Errorf("%v %v: got pointer %v", tt.format, tt.value, pointer)
+			}
+			got = fmt.Sprintf(format, args...)
+		})
+		if got != tt.want {
+			t.Errorf("%v %#v: got %q, want %q", tt.format, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package gen
+
+import (
+	"github.com/jhump/protoreflect/desc"
+)
+
+// wellKnownTypes maps the protobuf well-known types to the names detectType2
+// returns for them, each generator maps these to a native type
+var wellKnownTypes = map[string]string{
+	".google.protobuf.Struct":      "JSON",
+	".google.protobuf.Value":       "VALUE",
+	".google.protobuf.ListValue":   "LISTVALUE",
+	".google.protobuf.Any":         "ANY",
+	".google.protobuf.Empty":       "EMPTY",
+	".google.protobuf.Timestamp":   "TIMESTAMP",
+	".google.protobuf.Duration":    "DURATION",
+	".google.protobuf.FieldMask":   "FIELDMASK",
+	".google.protobuf.DoubleValue": "DOUBLEVALUE",
+	".google.protobuf.FloatValue":  "FLOATVALUE",
+	".google.protobuf.Int64Value":  "INT64VALUE",
+	".google.protobuf.UInt64Value": "UINT64VALUE",
+	".google.protobuf.Int32Value":  "INT32VALUE",
+	".google.protobuf.UInt32Value": "UINT32VALUE",
+	".google.protobuf.BoolValue":   "BOOLVALUE",
+	".google.protobuf.StringValue": "STRINGVALUE",
+	".google.protobuf.BytesValue":  "BYTESVALUE",
+}
+
+// wellKnownType returns the name of the well-known type of a field, see
+// wellKnownTypes, and whether the field is repeated or a map, in which case
+// the type is the one of the map values. It returns an empty name for
+// fields of other types and fields the proto doesn't have.
+func wellKnownType(service Service, message, field string) (string, bool, bool) {
+	files, err := parseProto(service)
+	if err != nil {
+		return "", false, false
+	}
+	msgDesc := files.message(message)
+	if msgDesc == nil {
+		return "", false, false
+	}
+	fieldDesc := msgDesc.FindFieldByName(field)
+	if fieldDesc == nil {
+		return "", false, false
+	}
+	if fieldDesc.IsMap() {
+		return wellKnownTypeOf(fieldDesc.GetMapValueType()), false, true
+	}
+	return wellKnownTypeOf(fieldDesc), fieldDesc.IsRepeated(), false
+}
+
+// wellKnownTypeOf returns the name of the well-known type of a single value
+// of a field, empty if it's not a well-known type
+func wellKnownTypeOf(field *desc.FieldDescriptor) string {
+	if field.GetMessageType() == nil {
+		return ""
+	}
+	return wellKnownTypes["."+field.GetMessageType().GetFullyQualifiedName()]
+}
+
+// usedWellKnownTypes returns the well-known types used by the messages of a
+// service, so the generators only add the helpers the service needs
+func usedWellKnownTypes(service Service) map[string]bool {
+	ret := map[string]bool{}
+	for _, m := range serviceMessages(service) {
+		for _, field := range m.GetFields() {
+			if field.IsMap() {
+				field = field.GetMapValueType()
+			}
+			if t := wellKnownTypeOf(field); t != "" {
+				ret[t] = true
+			}
+		}
+	}
+	return ret
+}
+
+// serviceMessages returns the messages of the schemas of a service and the
+// messages they use, by flat name
+func serviceMessages(service Service) map[string]*desc.MessageDescriptor {
+	ret := map[string]*desc.MessageDescriptor{}
+	files, err := parseProto(service)
+	if err != nil {
+		return ret
+	}
+	var add func(m *desc.MessageDescriptor)
+	add = func(m *desc.MessageDescriptor) {
+		if m.IsMapEntry() {
+			for _, field := range m.GetFields() {
+				if mt := field.GetMessageType(); mt != nil {
+					add(mt)
+				}
+			}
+			return
+		}
+		if _, ok := ret[flatName(m)]; ok || m.GetFile().GetPackage() == "google.protobuf" {
+			return
+		}
+		ret[flatName(m)] = m
+		for _, field := range m.GetFields() {
+			if mt := field.GetMessageType(); mt != nil {
+				add(mt)
+			}
+		}
+	}
+	for name := range service.Spec.Components.Schemas {
+		if m := files.message(name); m != nil {
+			add(m)
+		}
+	}
+	return ret
+}
+
+// fieldConversions returns, by message flat name, the fields of the messages
+// of a service that need converting to and from their JSON encoding, with
+// the kind of conversion: the kind kindOf returns for a single value, or the
+// name of a message with fields to convert. The kinds of repeated fields are
+// prefixed with "[]" and the kinds of map values with "{}".
+func fieldConversions(service Service, kindOf func(field *desc.FieldDescriptor) string) map[string]map[string]string {
+	messages := serviceMessages(service)
+	ret := map[string]map[string]string{}
+	// messages are converted once one of their fields is, repeat until no
+	// field is added to cover messages used before they're seen
+	for changed := true; changed; {
+		changed = false
+		for name, m := range messages {
+			for _, field := range m.GetFields() {
+				if _, ok := ret[name][field.GetName()]; ok {
+					continue
+				}
+				value, prefix := field, ""
+				if field.IsMap() {
+					value, prefix = field.GetMapValueType(), "{}"
+				} else if field.IsRepeated() {
+					prefix = "[]"
+				}
+				kind := kindOf(value)
+				if mt := value.GetMessageType(); kind == "" && mt != nil && len(ret[flatName(mt)]) > 0 {
+					kind = flatName(mt)
+				}
+				if kind == "" {
+					continue
+				}
+				if ret[name] == nil {
+					ret[name] = map[string]string{}
+				}
+				ret[name][field.GetName()] = prefix + kind
+				changed = true
+			}
+		}
+	}
+	return ret
+}
@@ -4,21 +4,20 @@ go 1.17
 
 require (
 	github.com/Masterminds/semver/v3 v3.1.1
-	github.com/crufter/nested v0.0.0-20210903145606-dea42c476b37
 	github.com/fatih/camelcase v1.0.0
 	github.com/getkin/kin-openapi v0.26.0
+	github.com/ghodss/yaml v1.0.0
 	github.com/google/go-github/v42 v42.0.0
 	github.com/jhump/protoreflect v1.12.0
 	github.com/stoewer/go-strcase v1.2.0
+	google.golang.org/protobuf v1.26.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/ghodss/yaml v1.0.0 // indirect
 	github.com/golang/protobuf v1.5.0 // indirect
 	github.com/google/go-querystring v1.1.0 // indirect
 	golang.org/x/crypto v0.0.0-20210817164053-32db794688a5 // indirect
 	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
-	google.golang.org/protobuf v1.26.0 // indirect
 	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
@@ -1,149 +1,135 @@
 package main
 
 import (
-	"encoding/json"
-	"flag"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"text/tabwriter"
 
-	"github.com/stoewer/go-strcase"
+	"github.com/m3o/m3o-client-gen/gen"
+)
+
+// exit codes, kept distinct so wrappers such as CI can tell a bad
+// invocation or bad specs apart from a failed generation run
+const (
+	exitOK       = 0
+	exitFailure  = 1
+	exitBadInput = 2
+	// the generated files on disk don't match the specs
+	exitStale = 3
 )
 
 func main() {
-	_ = flag.String("lang", "", "the language you want to generate m3o clients e.g go, dart, ts, bash ...")
-	flag.Parse()
+	os.Exit(run(os.Args[1:]))
+}
 
-	workDir, _ := os.Getwd()
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return exitBadInput
+	}
 
-	examplesPath := filepath.Join(workDir, "examples")
-	err := os.MkdirAll(examplesPath, FOLDER_EXECUTE_PERMISSION)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	// support the original `m3o-client-gen <lang>` form
+	if _, ok := gen.FindTarget(args[0]); ok {
+		return generateCmd(args)
 	}
 
-	switch flag.Arg(0) {
-	case "go":
-		goPath := filepath.Join(workDir, "clients", "go")
-		err = os.MkdirAll(goPath, FOLDER_EXECUTE_PERMISSION)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		goG := &goG{}
-		generate(goG, goPath, workDir, examplesPath)
-	case "dart":
-		dartPath := filepath.Join(workDir, "clients", "dart")
-		err = os.MkdirAll(dartPath, FOLDER_EXECUTE_PERMISSION)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
 		}
-		dartG := &dartG{}
-		generate(dartG, dartPath, workDir, examplesPath)
-	case "ts":
-		tsPath := filepath.Join(workDir, "clients", "ts")
-		err = os.MkdirAll(tsPath, FOLDER_EXECUTE_PERMISSION)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-		tsG := &tsG{}
-		generate(tsG, tsPath, workDir, examplesPath)
-	case "shell":
-		shellG := &shellG{}
-		generate(shellG, "", workDir, examplesPath)
-	case "cli":
-		cliG := &cliG{}
-		generate(cliG, "", workDir, examplesPath)
 	}
-}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage()
+		return exitOK
+	}
 
-func generate(g generator, path, workDir, examplesPath string) {
-	log.Println("statring generator ...")
-	log.Printf("path: %v\n", path)
-	log.Printf("workDir: %v\n", workDir)
-	log.Printf("examplePath: %v\n", examplesPath)
+	fmt.Fprintf(os.Stderr, "unknown command or target %q\n\n", args[0])
+	usage()
+	return exitBadInput
+}
 
-	files, err := ioutil.ReadDir(".")
-	if err != nil {
-		log.Fatal(err)
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: m3o-client-gen <command> [flags] [target...]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10v %v\n", c.name, c.description)
 	}
+	fmt.Fprintln(os.Stderr, "\nTargets:")
+	for _, t := range gen.Targets {
+		fmt.Fprintf(os.Stderr, "  %-10v %v\n", t.Name, t.Description)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'm3o-client-gen <command> -h' for the flags of a command.")
+}
 
-	services := []service{}
-
-	for _, f := range files {
+// serviceFilter selects services by name or glob pattern, an empty include
+// list selects every service
+type serviceFilter struct {
+	include listFlag
+	exclude listFlag
+}
 
-		if strings.Contains(f.Name(), "clients") || strings.Contains(f.Name(), "examples") {
+// match reports whether any of the given service names is selected
+func (f serviceFilter) match(names ...string) bool {
+	matches := func(patterns []string, name string) bool {
+		for _, p := range patterns {
+			if ok, _ := filepath.Match(p, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range names {
+		if matches(f.exclude, name) {
 			continue
 		}
-		if f.IsDir() && !strings.HasPrefix(f.Name(), ".") {
-			serviceName := f.Name()
-			serviceDir := filepath.Join(workDir, f.Name())
-			cmd := exec.Command("make", "api")
-			cmd.Dir = serviceDir
-			outp, err := cmd.CombinedOutput()
-			if err != nil {
-				fmt.Println(string(outp))
-			}
-			serviceFiles, err := ioutil.ReadDir(serviceDir)
-			if err != nil {
-				fmt.Println("Failed to read service dir", err)
-				os.Exit(1)
-			}
-			skip := false
-
-			spec, skip := apiSpec(serviceFiles, serviceDir)
-			if skip {
-				continue
-			}
-
-			service := service{
-				Name:       serviceName,
-				ImportName: serviceName,
-				Spec:       spec,
-			}
-			if service.Name == "function" {
-				service.ImportName = "fx"
-			}
-			services = append(services, service)
-
-			g.ServiceClient(serviceName, path, service)
-			g.TopReadme(serviceName, examplesPath, service)
-
-			exam, err := ioutil.ReadFile(filepath.Join(workDir, serviceName, "examples.json"))
-			if err != nil {
-                                exam, err = ioutil.ReadFile(filepath.Join(workDir, serviceName, "config", "examples.json"))
-                        }
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
-			}
-
-			m := map[string][]example{}
-			err = json.Unmarshal(exam, &m)
-			if err != nil {
-				fmt.Println(string(exam), err)
-				os.Exit(1)
-			}
-			if len(service.Spec.Paths) != len(m) {
-				fmt.Printf("Service has %v endpoints, but only %v examples\n", len(service.Spec.Paths), len(m))
-			}
-			for endpoint, examples := range m {
-				for _, example := range examples {
-					title := regexp.MustCompile("[^a-zA-Z0-9]+").ReplaceAllString(strcase.LowerCamelCase(strings.Replace(example.Title, " ", "_", -1)), "")
+		if len(f.include) == 0 || matches(f.include, name) {
+			return true
+		}
+	}
+	return false
+}
 
-					g.ExampleAndReadmeEdit(examplesPath, serviceName, endpoint, title, service, example)
-				}
-			}
+// serviceErrors returns the failures of a target, errors not tied to a
+// service are reported for the target as a whole
+func serviceErrors(target string, err error) []*gen.ServiceError {
+	errs := gen.MultiError{err}
+	if merr, ok := err.(gen.MultiError); ok {
+		errs = merr
+	}
+	ret := []*gen.ServiceError{}
+	for _, err := range errs {
+		serr, ok := err.(*gen.ServiceError)
+		if !ok {
+			serr = &gen.ServiceError{Err: err}
 		}
+		serr.Target = target
+		ret = append(ret, serr)
 	}
+	return ret
+}
 
-	g.IndexFile(path, services)
+// printFailures writes a summary table of what failed and why, only the
+// first line of each error is shown
+func printFailures(w io.Writer, failures []*gen.ServiceError) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%v failure(s):\n", len(failures))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tSERVICE\tENDPOINT\tEXAMPLE\tERROR")
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	for _, f := range failures {
+		msg := strings.SplitN(f.Err.Error(), "\n", 2)[0]
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", dash(f.Target), dash(f.Service), dash(f.Endpoint), dash(f.Example), msg)
+	}
+	tw.Flush()
 }
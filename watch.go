@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/m3o/m3o-client-gen/gen"
+)
+
+// watchCmd polls the inputs of every service, see gen.InputFiles, and
+// regenerates the services whose inputs changed along with the index files.
+// Failing services are reported and watching goes on.
+func watchCmd(args []string) int {
+	opts := &options{}
+	fs := opts.flagSet("watch")
+	interval := fs.Duration("interval", time.Second, "how often the service folders are checked for changes")
+	if code, ok := opts.parse(fs, args, true); !ok {
+		return code
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid -interval %v\n", *interval)
+		return exitBadInput
+	}
+
+	v := generatorVersion()
+	cache, err := gen.LoadCache(filepath.Join(opts.root, cacheFile), v)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	// see generateCmd
+	if v == "dev" {
+		cache.Services = map[string]string{}
+	}
+	opts.cache = cache
+	manifest, err := gen.LoadManifest(filepath.Join(opts.root, manifestFile))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	opts.manifest = manifest
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	hashes, err := watchHashes(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitFailure
+	}
+	fmt.Printf("watching %v service(s) in %v, press ctrl+c to stop\n", len(hashes), opts.root)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return exitOK
+		case <-ticker.C:
+		}
+
+		current, err := watchHashes(opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		changed := map[string]bool{}
+		removed := false
+		for name, hash := range current {
+			if hashes[name] != hash {
+				changed[name] = true
+			}
+		}
+		for name := range hashes {
+			if _, ok := current[name]; !ok {
+				removed = true
+			}
+		}
+		if len(changed) == 0 && !removed {
+			continue
+		}
+
+		regenerate(ctx, opts, changed)
+		// "make api" rewrites the api json of a service, take the hashes
+		// after the run so that doesn't count as another change
+		after, err := watchHashes(opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		hashes = after
+	}
+}
+
+// regenerate generates the changed services of every target, the index files
+// are always rewritten so services added or removed are picked up
+func regenerate(ctx context.Context, opts *options, changed map[string]bool) {
+	names := []string{}
+	for name := range changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("%v regenerating %v\n", time.Now().Format("15:04:05"), names)
+
+	previous := append(opts.manifest.Paths(), opts.manifest.Orphaned...)
+	failures := []*gen.ServiceError{}
+	for _, t := range opts.targets {
+		genOpts, err := opts.targetOptions(t, gen.DiskOutput{})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		genOpts.Match = func(serviceName string) bool {
+			return changed[serviceName]
+		}
+		if err := gen.Generate(ctx, genOpts); err != nil {
+			failures = append(failures, serviceErrors(t.Name, err)...)
+		}
+	}
+	printFailures(os.Stderr, failures)
+
+	if err := opts.cache.Save(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if err := pruneFiles(opts, previous, false); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// watchHashes hashes the inputs of the services selected by the filter,
+// keyed by service name. Skipped services are left out.
+func watchHashes(opts *options) (map[string]string, error) {
+	genOpts, err := opts.genOptions()
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]string{}
+	for _, name := range genOpts.Services {
+		if !opts.filter.match(name) || gen.Skipped(filepath.Join(opts.root, name)) {
+			continue
+		}
+		files, err := gen.InputFiles(name, genOpts)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := gen.HashInputs(files)
+		if err != nil {
+			return nil, err
+		}
+		ret[name] = hash
+	}
+	return ret, nil
+}